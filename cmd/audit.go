@@ -0,0 +1,173 @@
+/*
+Copyright © 2025 Syed Vilayat Ali Rizvi
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/vilayat-ali/gvm/internal"
+)
+
+// Audit result of a single installed version
+type versionAudit struct {
+	Version         string              `json:"version"`
+	GoVersion       string              `json:"go_version"`
+	Advisories      []internal.Advisory `json:"advisories"`
+	MinimumFixedVer string              `json:"minimum_fixed_version,omitempty"`
+}
+
+// auditCmd represents the audit command
+var auditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "List installed Go versions with known standard library vulnerabilities",
+	Long: `Check every installed Go version against a vulnerability feed in OSV format
+and list the standard library vulnerabilities affecting it, together with the
+minimum patch release fixing all of them.
+
+The feed defaults to the official Go vulnerability database and can be set with
+"advisory_feed" in the config or --feed. It may be a url or a local file, either
+a JSON array of OSV entries or a zip of OSV files, which allows offline audits.
+
+Exits with status 1 when any installed version is vulnerable.
+
+Examples:
+  gvm audit
+  gvm audit --feed ./osv-stdlib.json
+  gvm audit --json`,
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if !internal.ConfigExists() {
+			return fmt.Errorf("configuration not found. Please run 'gvm configure' first")
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		feed, _ := cmd.Flags().GetString("feed")
+		jsonOutput, _ := cmd.Flags().GetBool("json")
+
+		gvmConfig, err := internal.LoadConfig()
+		if err != nil {
+			color.Red(err.Error())
+			os.Exit(1)
+		}
+
+		if feed == "" {
+			feed = gvmConfig.AdvisoryFeed
+		}
+
+		entries, err := internal.LoadAdvisories(feed)
+		if err != nil {
+			color.Red(err.Error())
+			os.Exit(1)
+		}
+
+		names := make([]string, 0, len(gvmConfig.DownloadedVersions))
+		for name := range gvmConfig.DownloadedVersions {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		audits := make([]versionAudit, 0, len(names))
+		vulnerable := 0
+
+		for _, name := range names {
+			downloadVersion := gvmConfig.DownloadedVersions[name]
+			goVersion := downloadVersion.Version
+			if downloadVersion.IsLink() {
+				if goVersion, err = internal.ReadGoRootVersion(downloadVersion.LinkPath); err != nil {
+					continue
+				}
+			}
+
+			advisories := internal.StdlibAdvisories(entries, goVersion)
+			audit := versionAudit{
+				Version:         name,
+				GoVersion:       goVersion,
+				Advisories:      advisories,
+				MinimumFixedVer: internal.MinimumFixedVersion(advisories),
+			}
+			if len(advisories) > 0 {
+				vulnerable++
+			}
+			audits = append(audits, audit)
+		}
+
+		if jsonOutput {
+			data, err := json.MarshalIndent(audits, "", "  ")
+			if err != nil {
+				color.Red(err.Error())
+				os.Exit(1)
+			}
+			fmt.Println(string(data))
+		} else {
+			printAudits(audits)
+		}
+
+		if vulnerable > 0 {
+			os.Exit(1)
+		}
+	},
+}
+
+func printAudits(audits []versionAudit) {
+	fmt.Println()
+	color.Cyan("🛡️  Security Audit")
+	fmt.Println(strings.Repeat("─", 60))
+
+	if len(audits) == 0 {
+		color.Yellow("📭 No downloaded Go versions found.")
+		return
+	}
+
+	for _, audit := range audits {
+		if len(audit.Advisories) == 0 {
+			color.Green("  ✓ %s: no known vulnerabilities", audit.Version)
+			continue
+		}
+
+		fix := "no fixed release yet"
+		if audit.MinimumFixedVer != "" {
+			fix = "fixed in " + audit.MinimumFixedVer
+		}
+		color.New(color.FgRed, color.Bold).Printf("  ✗ %s: %d vulnerabilit(ies), %s\n", audit.Version, len(audit.Advisories), fix)
+
+		for _, advisory := range audit.Advisories {
+			fixedIn := advisory.FixedIn
+			if fixedIn == "" {
+				fixedIn = "unfixed"
+			}
+			color.HiBlack("      %s (%s) %s", advisory.ID, fixedIn, advisory.Summary)
+		}
+	}
+	fmt.Println()
+}
+
+func init() {
+	auditCmd.Flags().String("feed", "", "OSV feed url or local file, overrides the configured one")
+	auditCmd.Flags().Bool("json", false, "Print the audit as JSON")
+	rootCmd.AddCommand(auditCmd)
+}
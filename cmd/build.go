@@ -0,0 +1,139 @@
+/*
+Copyright © 2025 Syed Vilayat Ali Rizvi
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/vilayat-ali/gvm/internal"
+)
+
+// buildCmd represents the build command
+var buildCmd = &cobra.Command{
+	Use:   "build [package] [-- go build flags]",
+	Short: "Cross-compile a package for several platforms with a pinned Go version",
+	Long: `Run 'go build' across a GOOS/GOARCH target matrix using a downloaded gvm
+toolchain, without switching the globally active Go version.
+
+Binaries are written to <out>/<goos>_<goarch>/<name> together with a
+SHA256SUMS file. CGO is disabled unless CGO_ENABLED is set in the environment.
+Arguments after '--' are passed to 'go build' unchanged.
+
+Examples:
+  gvm build --targets linux/amd64,darwin/arm64
+  gvm build -g 1.25.5 -t linux/amd64,windows/amd64 ./cmd/server
+  gvm build -t linux/arm64 -- -trimpath -ldflags "-s -w"`,
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if !internal.ConfigExists() {
+			return fmt.Errorf("configuration not found. Please run 'gvm configure' first")
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		targetSpec, _ := cmd.Flags().GetString("targets")
+		requestedVersion, _ := cmd.Flags().GetString("version")
+		outDir, _ := cmd.Flags().GetString("out")
+		name, _ := cmd.Flags().GetString("name")
+
+		targets, err := internal.ParseBuildTargets(targetSpec)
+		if err != nil {
+			color.Red(err.Error())
+			os.Exit(1)
+		}
+
+		pkgArgs, extraArgs := args, []string{}
+		if dash := cmd.ArgsLenAtDash(); dash >= 0 {
+			pkgArgs, extraArgs = args[:dash], args[dash:]
+		}
+
+		pkg := "."
+		if len(pkgArgs) > 1 {
+			color.Red("Arg Error: Expected at most one package to build")
+			os.Exit(1)
+		} else if len(pkgArgs) == 1 {
+			pkg = pkgArgs[0]
+		}
+
+		name, err = internal.BuildBinaryName(pkg, name)
+		if err != nil {
+			color.Red(err.Error())
+			os.Exit(1)
+		}
+
+		gvmConfig, err := internal.LoadConfig()
+		if err != nil {
+			color.Red(err.Error())
+			os.Exit(1)
+		}
+
+		if requestedVersion == "" {
+			currentVersion, err := internal.GetCurrentGolangVersion()
+			if err != nil || *currentVersion == internal.NoGoVersion {
+				color.Red("Input Error: No active Go version found. Pass one with --version")
+				os.Exit(1)
+			}
+			requestedVersion = *currentVersion
+		}
+
+		downloadVersion := gvmConfig.ResolveDownloadedVersion(requestedVersion)
+		if downloadVersion == nil {
+			color.Red(fmt.Sprintf("Input Error: Go version %s is not downloaded. Run 'gvm download %s' first", requestedVersion, requestedVersion))
+			os.Exit(1)
+		}
+
+		goroot, err := downloadVersion.GoRoot()
+		if err != nil {
+			color.Red(err.Error())
+			os.Exit(1)
+		}
+
+		color.Blue(fmt.Sprintf("Building %s with %s for %d target(s)", pkg, downloadVersion.Version, len(targets)))
+		artifacts, err := internal.CrossBuild(goroot, targets, pkg, name, outDir, extraArgs)
+		if err != nil {
+			color.Red(err.Error())
+			os.Exit(1)
+		}
+
+		if err := internal.RecordVersionUsage(downloadVersion.Version); err != nil {
+			color.Yellow("Failed to record usage of %s: %s", downloadVersion.Version, err.Error())
+		}
+
+		fmt.Println()
+		for _, artifact := range artifacts {
+			color.Green(fmt.Sprintf("  ✓ %-16s %s", artifact.Target, filepath.Join(outDir, artifact.Path)))
+		}
+		color.Cyan(fmt.Sprintf("\nChecksums written to %s", filepath.Join(outDir, internal.ChecksumFile)))
+	},
+}
+
+func init() {
+	buildCmd.Flags().StringP("targets", "t", "", "Comma separated build targets (e.g., linux/amd64,darwin/arm64)")
+	buildCmd.Flags().StringP("version", "g", "", "Go version to build with (defaults to the active version)")
+	buildCmd.Flags().StringP("out", "o", "dist", "Output directory for the artifacts")
+	buildCmd.Flags().String("name", "", "Binary name (defaults to the package directory name)")
+	buildCmd.MarkFlagRequired("targets")
+	rootCmd.AddCommand(buildCmd)
+}
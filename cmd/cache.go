@@ -0,0 +1,217 @@
+/*
+Copyright © 2025 Syed Vilayat Ali Rizvi
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/vilayat-ali/gvm/internal"
+)
+
+// cacheCmd represents the cache command
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Manage the shared download cache",
+	Long: `Manage the content addressed download cache shared by every user of this machine.
+
+Downloaded archives are stored once under their sha256 and hardlinked (or
+copied) into each user's ~/.local/share/gvm/go-versions, so a version already
+downloaded by another user is never fetched again.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := cmd.Help(); err != nil {
+			fmt.Println(err)
+		}
+	},
+}
+
+var cacheListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List cached archives",
+	Run: func(cmd *cobra.Command, args []string) {
+		entries, err := internal.ListCache()
+		if err != nil {
+			color.Red("✗ %s", err.Error())
+			os.Exit(1)
+		}
+
+		if len(entries) == 0 {
+			color.Yellow("📭 The download cache is empty.")
+			return
+		}
+
+		fmt.Println()
+		color.Cyan("🗄️  Download Cache")
+		fmt.Println(strings.Repeat("─", 70))
+
+		var totalSize int64
+		for _, entry := range entries {
+			totalSize += entry.Size
+			color.New(color.FgMagenta).Printf("  • %-12s %10s  %-10s %s\n",
+				internal.ShortChecksum(entry.SHA256),
+				internal.FormatBytes(entry.Size),
+				formatAge(time.Since(entry.LastUsed)),
+				strings.Join(entry.Names, ", "),
+			)
+		}
+
+		fmt.Println(strings.Repeat("─", 70))
+		color.Cyan("  %d archive(s), %s total", len(entries), internal.FormatBytes(totalSize))
+		fmt.Println()
+	},
+}
+
+var cachePruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Evict archives by age or total cache size",
+	Long: `Evict archives from the download cache.
+
+Archives unused for longer than --older-than are removed, then the least
+recently used ones until the cache fits into --max-size. Copies already linked
+into a user's view stay usable until that user removes them.
+
+Examples:
+  gvm cache prune --older-than 90d
+  gvm cache prune --max-size 2GB --dry-run`,
+	Run: func(cmd *cobra.Command, args []string) {
+		olderThanFlag, _ := cmd.Flags().GetString("older-than")
+		maxSizeFlag, _ := cmd.Flags().GetString("max-size")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+		var olderThan time.Duration
+		var maxSize int64
+		var err error
+
+		if olderThanFlag != "" {
+			if olderThan, err = internal.ParseAge(olderThanFlag); err != nil {
+				color.Red(err.Error())
+				os.Exit(1)
+			}
+		}
+		if maxSizeFlag != "" {
+			if maxSize, err = internal.ParseSize(maxSizeFlag); err != nil {
+				color.Red(err.Error())
+				os.Exit(1)
+			}
+		}
+
+		if olderThan == 0 && maxSize == 0 {
+			color.Red("Arg Error: Expected at least one of --older-than or --max-size")
+			os.Exit(1)
+		}
+
+		entries, err := internal.ListCache()
+		if err != nil {
+			color.Red("✗ %s", err.Error())
+			os.Exit(1)
+		}
+
+		evictions := internal.SelectCacheEvictions(entries, olderThan, maxSize, time.Now())
+		if len(evictions) == 0 {
+			color.Green("✓ Nothing to prune")
+			return
+		}
+
+		var freed int64
+		for _, entry := range evictions {
+			if dryRun {
+				color.Yellow("  would remove %s (%s) %s", internal.ShortChecksum(entry.SHA256), internal.FormatBytes(entry.Size), strings.Join(entry.Names, ", "))
+			} else {
+				if err := internal.RemoveCacheEntry(&entry); err != nil {
+					color.Red("✗ %s", err.Error())
+					os.Exit(1)
+				}
+				color.Yellow("  removed %s (%s) %s", internal.ShortChecksum(entry.SHA256), internal.FormatBytes(entry.Size), strings.Join(entry.Names, ", "))
+			}
+			freed += entry.Size
+		}
+
+		if dryRun {
+			color.Cyan("\nDry run: %s would be freed", internal.FormatBytes(freed))
+		} else {
+			color.Green("\n✓ Freed %s", internal.FormatBytes(freed))
+		}
+	},
+}
+
+var cacheVerifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Check cached archives against their sha256",
+	Run: func(cmd *cobra.Command, args []string) {
+		remove, _ := cmd.Flags().GetBool("remove-corrupt")
+
+		entries, err := internal.ListCache()
+		if err != nil {
+			color.Red("✗ %s", err.Error())
+			os.Exit(1)
+		}
+
+		corrupt := 0
+		for _, entry := range entries {
+			if err := internal.VerifyCacheEntry(&entry); err != nil {
+				corrupt++
+				color.Red("  ✗ %s", err.Error())
+				if remove {
+					if err := internal.RemoveCacheEntry(&entry); err != nil {
+						color.Red("  ✗ %s", err.Error())
+					}
+				}
+				continue
+			}
+			color.Green("  ✓ %s %s", internal.ShortChecksum(entry.SHA256), strings.Join(entry.Names, ", "))
+		}
+
+		if corrupt > 0 {
+			color.Red("\n%d of %d archive(s) are corrupt", corrupt, len(entries))
+			os.Exit(1)
+		}
+		color.Green("\n✓ All %d archive(s) verified", len(entries))
+	},
+}
+
+// Formats how long ago something happened, e.g. "3d ago".
+func formatAge(age time.Duration) string {
+	switch {
+	case age < time.Hour:
+		return fmt.Sprintf("%dm ago", int(age.Minutes()))
+	case age < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(age.Hours()))
+	default:
+		return fmt.Sprintf("%dd ago", int(age.Hours()/24))
+	}
+}
+
+func init() {
+	cachePruneCmd.Flags().String("older-than", "", "Remove archives unused for longer than this (e.g., 90d, 72h)")
+	cachePruneCmd.Flags().String("max-size", "", "Shrink the cache to at most this size (e.g., 2GB)")
+	cachePruneCmd.Flags().Bool("dry-run", false, "Only show what would be removed")
+	cacheVerifyCmd.Flags().Bool("remove-corrupt", false, "Remove archives failing verification")
+
+	cacheCmd.AddCommand(cacheListCmd)
+	cacheCmd.AddCommand(cachePruneCmd)
+	cacheCmd.AddCommand(cacheVerifyCmd)
+	rootCmd.AddCommand(cacheCmd)
+}
@@ -0,0 +1,164 @@
+/*
+Copyright © 2025 Syed Vilayat Ali Rizvi
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/vilayat-ali/gvm/internal"
+)
+
+// checkCmd represents the check command
+var checkCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Check the active Go version against the project's requirements",
+	Long: `Check that the active Go version satisfies the nearest .go-version, the go
+and toolchain directives of the nearest go.mod and gvm.lock, and that these
+agree with each other, e.g. that go.mod doesn't require a newer Go than
+.go-version pins. Exits with status 1 when any check fails, for use as a
+CI gate.
+
+The active version is determined like 'gvm current' does. A .go-version
+pin without a patch number (e.g. "1.25") accepts any release of its minor
+line, the go and toolchain directives of go.mod name minimum versions.
+
+With --json the report is printed as JSON. Every check carries the file
+and line of the requirement, e.g. for CI annotations.
+
+Examples:
+  gvm check
+  gvm check --json`,
+	Run: func(cmd *cobra.Command, args []string) {
+		asJSON, _ := cmd.Flags().GetBool("json")
+
+		// stdout holds nothing but the report in --json mode
+		fail := func(err error) {
+			if asJSON {
+				color.New(color.FgRed).Fprintln(os.Stderr, err.Error())
+			} else {
+				color.Red(err.Error())
+			}
+			os.Exit(1)
+		}
+
+		cwd, err := os.Getwd()
+		if err != nil {
+			fail(err)
+		}
+
+		active, err := internal.ResolveActiveVersion(cwd)
+		if err != nil {
+			fail(err)
+		}
+
+		report := internal.CheckToolchainConsistency(cwd, active)
+
+		if asJSON {
+			encoder := json.NewEncoder(os.Stdout)
+			encoder.SetIndent("", "  ")
+			encoder.SetEscapeHTML(false)
+			if err := encoder.Encode(report); err != nil {
+				fail(err)
+			}
+			if !report.OK {
+				os.Exit(1)
+			}
+			return
+		}
+
+		fmt.Println()
+		if active != nil {
+			color.Cyan("🔎 Checking %s (%s)", active.Version, describeVersionSource(active.Source))
+		} else {
+			color.Cyan("🔎 Checking Go toolchain")
+		}
+		fmt.Println(strings.Repeat("─", 60))
+
+		if len(report.Checks) == 0 {
+			color.Yellow("No .go-version, go.mod or %s found in %s or its parents", internal.LockFileName, cwd)
+			return
+		}
+
+		failed := 0
+		for _, check := range report.Checks {
+			if check.OK {
+				color.Green("  ✓ %-22s %s", check.Name, check.Message)
+				continue
+			}
+
+			failed++
+			color.Red("  ✗ %-22s %s", check.Name, check.Message)
+			color.Red("      - %-20s %s", check.Expected, checkLocation(check))
+			color.Green("      + %-20s %s", check.Actual, checkActualSource(check))
+		}
+
+		if failed > 0 {
+			color.Red("\n✗ %d of %d check(s) failed", failed, len(report.Checks))
+			os.Exit(1)
+		}
+		color.Green("\n✓ All %d check(s) passed", len(report.Checks))
+	},
+}
+
+// Formats the file and line of a check's requirement, relative to the
+// working directory when possible.
+func checkLocation(check internal.ToolchainCheck) string {
+	if check.File == "" {
+		return ""
+	}
+
+	location := check.File
+	if cwd, err := os.Getwd(); err == nil {
+		if relative, err := filepath.Rel(cwd, check.File); err == nil {
+			location = relative
+		}
+	}
+	if check.Line > 0 {
+		location += fmt.Sprintf(":%d", check.Line)
+	}
+	return location
+}
+
+// Describes where the actual value of a failed check comes from.
+func checkActualSource(check internal.ToolchainCheck) string {
+	switch check.Name {
+	case internal.CheckPinConflict:
+		return internal.GoVersionFile
+	case internal.CheckLockfile:
+		return ""
+	case internal.CheckLockedSHA256:
+		return "installed archive"
+	default:
+		return "active"
+	}
+}
+
+func init() {
+	checkCmd.Flags().Bool("json", false, "Print the report as JSON")
+	rootCmd.AddCommand(checkCmd)
+}
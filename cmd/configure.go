@@ -41,13 +41,24 @@ This command performs the following actions:
 
 If configuration already exists, this command will inform you that gvm is already set up.
 
+The remote version source can be selected with --version-source:
+  go.dev   official JSON release feed
+  file     release index in the go.dev JSON format read from --version-source-url
+  mirror   go.dev/dl mirror served at --version-source-url
+  github   tags page of the golang github repository (default)
+
 Examples:
   gvm configure      # Initializes gvm with default settings
+  gvm configure --version-source go.dev
+  gvm configure --version-source mirror --version-source-url https://mirror.example.com/golang
   gvm configure -h   # Shows help information for this command`,
 	Run: func(cmd *cobra.Command, args []string) {
+		sourceKind, _ := cmd.Flags().GetString("version-source")
+		sourceURL, _ := cmd.Flags().GetString("version-source-url")
+
 		if !internal.ConfigExists() {
 			color.Blue("Setting up gvm configuration...")
-			if err := internal.SetupConfig(); err != nil {
+			if err := internal.SetupConfigWithSource(sourceKind, sourceURL); err != nil {
 				color.Red("Failed to configure gvm: %s", err.Error())
 				os.Exit(1)
 			}
@@ -63,5 +74,7 @@ Examples:
 }
 
 func init() {
+	configureCmd.Flags().String("version-source", "", "Remote version source (go.dev, file, mirror or github)")
+	configureCmd.Flags().String("version-source-url", "", "Index file path or mirror url for the file and mirror version sources")
 	rootCmd.AddCommand(configureCmd)
 }
@@ -0,0 +1,162 @@
+/*
+Copyright © 2025 Syed Vilayat Ali Rizvi
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/vilayat-ali/gvm/internal"
+)
+
+// currentCmd represents the current command
+var currentCmd = &cobra.Command{
+	Use:   "current",
+	Short: "Show the active Go version",
+	Long: `Show the Go version a 'go' command run in the current directory uses.
+
+With the gvm shims first in PATH (see 'gvm rehash') the version is chosen, in
+order of precedence, by the GVM_GO_VERSION environment variable ('gvm shell'),
+the nearest .go-version file, the nearest go.mod and the default version set
+with 'gvm use'. Otherwise it is the Go installation found in PATH.
+
+Examples:
+  gvm current
+  gvm current --source
+  gvm current --json`,
+	Run: func(cmd *cobra.Command, args []string) {
+		showSource, _ := cmd.Flags().GetBool("source")
+		asJSON, _ := cmd.Flags().GetBool("json")
+
+		active := resolveActiveVersion()
+
+		if asJSON {
+			data, err := json.MarshalIndent(active, "", "  ")
+			if err != nil {
+				color.Red(err.Error())
+				os.Exit(1)
+			}
+			fmt.Println(string(data))
+			if active == nil || !active.Installed {
+				os.Exit(1)
+			}
+			return
+		}
+
+		if active == nil {
+			color.Yellow("none")
+			color.HiBlack("No Go version found. Run 'gvm use <version>' to install one.")
+			os.Exit(1)
+		}
+
+		if !showSource {
+			fmt.Println(active.Version)
+			if !active.Installed {
+				color.Red("Go %s is not installed. Run 'gvm install %s'", active.Version, active.Version)
+				os.Exit(1)
+			}
+			return
+		}
+
+		fmt.Printf("%s (%s)\n", active.Version, describeVersionSource(active.Source))
+		if active.GoRoot != "" {
+			color.HiBlack("GOROOT %s", active.GoRoot)
+		}
+		if !active.Installed {
+			color.Red("Go %s is not installed. Run 'gvm install %s'", active.Version, active.Version)
+			os.Exit(1)
+		}
+	},
+}
+
+// whichCmd represents the which command
+var whichCmd = &cobra.Command{
+	Use:   "which <tool>",
+	Short: "Show the path of a tool of the active Go version",
+	Long: `Print the absolute path of a binary (e.g. go or gofmt) of the Go version
+active in the current directory, see 'gvm current --source'. For shims the
+path of the real toolchain binary is printed.
+
+Examples:
+  gvm which go
+  gvm which gofmt`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		active := resolveActiveVersion()
+		if active == nil {
+			color.Red("No Go version found. Run 'gvm use <version>' to install one.")
+			os.Exit(1)
+		}
+
+		toolPath, err := active.ToolPath(args[0])
+		if err != nil {
+			color.Red(err.Error())
+			os.Exit(1)
+		}
+
+		fmt.Println(toolPath)
+	},
+}
+
+// Resolves the active version of the working directory, exiting on errors.
+func resolveActiveVersion() *internal.ActiveVersion {
+	dir, err := os.Getwd()
+	if err != nil {
+		color.Red(err.Error())
+		os.Exit(1)
+	}
+
+	active, err := internal.ResolveActiveVersion(dir)
+	if err != nil {
+		color.Red(err.Error())
+		os.Exit(1)
+	}
+
+	return active
+}
+
+// Explains where a version came from, for humans.
+func describeVersionSource(source string) string {
+	switch {
+	case source == internal.VersionEnvVar:
+		return fmt.Sprintf("set by %s, see 'gvm shell'", internal.VersionEnvVar)
+	case source == internal.DefaultVersionSource:
+		return "default version set by 'gvm use'"
+	case source == internal.PathVersionSource:
+		return "go found in PATH"
+	case strings.HasSuffix(source, "go.mod"):
+		return fmt.Sprintf("required by %s", source)
+	default:
+		return fmt.Sprintf("set by %s", source)
+	}
+}
+
+func init() {
+	currentCmd.Flags().Bool("source", false, "Show how the version was chosen")
+	currentCmd.Flags().Bool("json", false, "Print the version, its source and GOROOT as JSON")
+	rootCmd.AddCommand(currentCmd)
+	rootCmd.AddCommand(whichCmd)
+}
@@ -24,7 +24,7 @@ package cmd
 import (
 	"fmt"
 	"os"
-	"strings"
+	"path/filepath"
 
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
@@ -69,38 +69,65 @@ Examples:
 			os.Exit(1)
 		}
 
-		var remoteVersion *internal.RemoteVersion
-		var remoteVersionIdx int = -1
-
-		for idx, rv := range gvmConfig.AvailableVersions {
-			if strings.Replace(rv.Version, "go", "", 1) == strings.TrimSpace(requestedVersion) {
-				remoteVersion = &rv
-				remoteVersionIdx = idx
-			}
-		}
-
-		if remoteVersion == nil || remoteVersionIdx == -1 {
+		remoteVersion := gvmConfig.FindAvailableVersion(requestedVersion)
+		if remoteVersion == nil {
 			color.Red(fmt.Sprintf("Download Error: Failed to download '%s'. Couldn't find in config available versions", requestedVersion))
 			os.Exit(1)
 		}
 
 		color.Green(fmt.Sprintf("Downloading %s\n", requestedVersion))
-		path, err := remoteVersion.Download()
-		if err != nil {
-			color.Red(err.Error())
-			os.Exit(1)
-		}
+		path := downloadVerified(cmd, gvmConfig, remoteVersion)
 
-		if err := gvmConfig.MarkVersionAsDownloaded(remoteVersion, *path); err != nil {
+		if err := gvmConfig.MarkVersionAsDownloaded(remoteVersion, path); err != nil {
 			color.Red(err.Error())
 			os.Exit(1)
 		}
 
-		color.Green(fmt.Sprintf("\nGo version %s was downloaded and saved in %s", remoteVersion.Version, *path))
+		color.Green(fmt.Sprintf("\nGo version %s was downloaded and saved in %s", remoteVersion.Version, path))
 	},
 }
 
+// Downloads a release and verifies its version and signature before it
+// enters the shared cache, exiting when they are rejected. Returns the
+// path of the archive in the per user view.
+func downloadVerified(cmd *cobra.Command, gvmConfig *internal.Config, remoteVersion *internal.RemoteVersion) string {
+	requireSignature, _ := cmd.Flags().GetBool("require-signature")
+
+	path, signed, err := gvmConfig.DownloadVerified(remoteVersion, true, requireSignature)
+	if err != nil {
+		color.Red(err.Error())
+		os.Exit(1)
+	}
+
+	reportSignature(gvmConfig, path, signed)
+	return path
+}
+
+// Checks the detached signature of an archive against the configured
+// keys and exits when the signature policy rejects it.
+func verifySignature(cmd *cobra.Command, gvmConfig *internal.Config, archivePath string, loadSignature func(ext string) ([]byte, error)) {
+	requireSignature, _ := cmd.Flags().GetBool("require-signature")
+
+	signed, err := gvmConfig.VerifySignature(archivePath, loadSignature, requireSignature)
+	if err != nil {
+		color.Red(err.Error())
+		os.Exit(1)
+	}
+
+	reportSignature(gvmConfig, archivePath, signed)
+}
+
+func reportSignature(gvmConfig *internal.Config, archivePath string, signed bool) {
+	switch {
+	case signed:
+		color.Green("✓ Signature verified")
+	case len(gvmConfig.SignatureKeys) > 0:
+		color.Yellow(fmt.Sprintf("⚠ %s is not signed, continuing without signature verification", filepath.Base(archivePath)))
+	}
+}
+
 func init() {
 	downloadCmd.Flags().StringP("version", "g", "", "Go version to download (e.g., 1.25.5)")
+	downloadCmd.Flags().Bool("require-signature", false, "Fail when the archive has no valid signature of a configured key")
 	rootCmd.AddCommand(downloadCmd)
 }
@@ -0,0 +1,313 @@
+/*
+Copyright © 2025 Syed Vilayat Ali Rizvi
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/vilayat-ali/gvm/internal"
+)
+
+// installCmd represents the install command
+var installCmd = &cobra.Command{
+	Use:   "install [version]",
+	Short: "Install a Go version from the remote index or a local archive",
+	Long: `Install a Go version.
+
+Without flags this behaves like 'gvm download <version>'. With --file a release
+archive obtained out of band (e.g. on air-gapped machines) is registered
+instead. The version is detected from the archive's go/VERSION file and no
+network access is performed. Archives built for another platform than this
+one are rejected unless --force is given.
+
+With --source Go is built from source using an already downloaded gvm
+toolchain as GOROOT_BOOTSTRAP. The source is either a local git checkout of
+the go repository (built at its HEAD commit, without network access) or a
+branch, tag or commit fetched from go.googlesource.com. The result is
+registered as 'devel-<sha>'.
+
+With --frozen the toolchains recorded in the project's gvm.lock are
+installed, or only the given version which must be locked. The install
+fails when gvm.lock is out of date with .go-version, go.mod or gvm.toml,
+or when the version source serves another archive than the locked one.
+
+Examples:
+  gvm install 1.25.5
+  gvm install --file go1.25.5.linux-amd64.tar.gz
+  gvm install --file go1.25.5.linux-amd64.tar.gz --sha256 <checksum>
+  gvm install --file go1.25.5.linux-amd64.tar.gz --require-signature
+  gvm install --source master
+  gvm install --source release-branch.go1.25 --bootstrap 1.24.11
+  gvm install --source ~/src/go
+  gvm install --frozen
+  gvm install --frozen 1.25.5`,
+	Run: func(cmd *cobra.Command, args []string) {
+		archivePath, _ := cmd.Flags().GetString("file")
+		checksum, _ := cmd.Flags().GetString("sha256")
+		source, _ := cmd.Flags().GetString("source")
+		frozen, _ := cmd.Flags().GetBool("frozen")
+		force, _ := cmd.Flags().GetBool("force")
+
+		if frozen {
+			if archivePath != "" || source != "" {
+				color.Red("Arg Error: --frozen can't be combined with --file or --source")
+				os.Exit(1)
+			}
+			installFrozen(cmd, args)
+			return
+		}
+
+		if source != "" {
+			installFromSource(cmd, source)
+			return
+		}
+
+		if archivePath == "" {
+			if !internal.ConfigExists() {
+				color.Red("configuration not found. Please run 'gvm configure' first")
+				os.Exit(1)
+			}
+			downloadCmd.Run(cmd, args)
+			return
+		}
+
+		if !internal.ConfigExists() {
+			color.Blue("Setting up gvm configuration (offline)...")
+			if err := internal.SetupOfflineConfig(); err != nil {
+				color.Red("Failed to configure gvm: %s", err.Error())
+				os.Exit(1)
+			}
+		}
+
+		gvmConfig, err := internal.LoadConfig()
+		if err != nil {
+			color.Red(err.Error())
+			os.Exit(1)
+		}
+
+		// verified before the import so a rejected archive never enters the cache
+		signatureLoader := internal.LocalSignatureLoader(archivePath)
+		if signaturePath, _ := cmd.Flags().GetString("signature"); signaturePath != "" {
+			signatureLoader = func(ext string) ([]byte, error) {
+				return os.ReadFile(signaturePath)
+			}
+		}
+		verifySignature(cmd, gvmConfig, archivePath, signatureLoader)
+
+		color.Blue(fmt.Sprintf("Importing %s", archivePath))
+		downloadVersion, err := internal.ImportLocalArchive(archivePath, checksum, force)
+		if err != nil {
+			color.Red(err.Error())
+			os.Exit(1)
+		}
+
+		if err := gvmConfig.MarkVersionAsDownloaded(&internal.RemoteVersion{Version: downloadVersion.Version, SHA256: downloadVersion.SHA256}, downloadVersion.TarPath); err != nil {
+			color.Red(err.Error())
+			os.Exit(1)
+		}
+
+		color.Green(fmt.Sprintf("Go version %s was installed from %s and saved in %s", downloadVersion.Version, archivePath, downloadVersion.TarPath))
+	},
+}
+
+func installFrozen(cmd *cobra.Command, args []string) {
+	if !internal.ConfigExists() {
+		color.Red("configuration not found. Please run 'gvm configure' first")
+		os.Exit(1)
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		color.Red(err.Error())
+		os.Exit(1)
+	}
+
+	lockPath, ok := internal.FindLockfile(cwd)
+	if !ok {
+		color.Red(fmt.Sprintf("Frozen Error: no %s found in %s or its parents, run 'gvm lock' first", internal.LockFileName, cwd))
+		os.Exit(1)
+	}
+
+	lockfile, err := internal.LoadLockfile(lockPath)
+	if err != nil {
+		color.Red(err.Error())
+		os.Exit(1)
+	}
+
+	requests, _, err := internal.ProjectLockRequests(cwd, nil)
+	if err != nil {
+		color.Red(err.Error())
+		os.Exit(1)
+	}
+
+	if problems := lockfile.Outdated(requests); problems != nil {
+		for _, problem := range problems {
+			color.Red("  • %s", problem)
+		}
+		color.Red(fmt.Sprintf("Frozen Error: %s is out of date, run 'gvm lock'", lockPath))
+		os.Exit(1)
+	}
+
+	gvmConfig, err := internal.LoadConfig()
+	if err != nil {
+		color.Red(err.Error())
+		os.Exit(1)
+	}
+
+	if len(args) > 0 {
+		locked := lockfile.FindVersion(args[0])
+		if locked == nil {
+			color.Red(fmt.Sprintf("Frozen Error: %s is not locked in %s", args[0], lockPath))
+			os.Exit(1)
+		}
+		installLocked(cmd, gvmConfig, locked)
+		return
+	}
+
+	platform := internal.CurrentPlatform()
+	for _, locked := range lockfile.Toolchains {
+		if _, ok := locked.Artifacts[platform]; !ok {
+			color.HiBlack(fmt.Sprintf("Skipping %s (%s), not locked for %s", locked.Name, locked.Version, platform))
+			continue
+		}
+		installLocked(cmd, gvmConfig, &locked)
+	}
+}
+
+// Installs the archive locked for the running platform, exiting when it
+// differs from what the version source serves or what is installed.
+func installLocked(cmd *cobra.Command, gvmConfig *internal.Config, locked *internal.LockedToolchain) {
+	downloaded := gvmConfig.ResolveDownloadedVersion(locked.Version)
+	if downloaded != nil && !downloaded.IsMissing() {
+		checksum := downloaded.SHA256
+		if checksum == "" && !downloaded.IsLink() {
+			checksum, _ = internal.FileSHA256(downloaded.TarPath)
+		}
+
+		expected := locked.Artifacts[internal.CurrentPlatform()].SHA256
+		if expected != "" && !strings.EqualFold(checksum, expected) {
+			color.Red(fmt.Sprintf("Frozen Error: installed %s doesn't match the sha256 %s locked in %s", downloaded.Version, expected, internal.LockFileName))
+			os.Exit(1)
+		}
+
+		color.Green(fmt.Sprintf("✓ %s is already installed", downloaded.Version))
+		return
+	}
+
+	remoteVersion, err := gvmConfig.FrozenRemoteVersion(locked)
+	if err != nil {
+		color.Red(err.Error())
+		os.Exit(1)
+	}
+
+	color.Green(fmt.Sprintf("Downloading %s\n", remoteVersion.Version))
+	path := downloadVerified(cmd, gvmConfig, remoteVersion)
+
+	// replace an entry whose files went missing
+	if downloaded != nil {
+		delete(gvmConfig.DownloadedVersions, downloaded.Version)
+	}
+	if err := gvmConfig.MarkVersionAsDownloaded(remoteVersion, path); err != nil {
+		color.Red(err.Error())
+		os.Exit(1)
+	}
+
+	color.Green(fmt.Sprintf("✓ Go version %s was installed from %s", remoteVersion.Version, remoteVersion.DownloadLink))
+}
+
+func installFromSource(cmd *cobra.Command, source string) {
+	if !internal.ConfigExists() {
+		color.Red("configuration not found. Please run 'gvm configure' first")
+		os.Exit(1)
+	}
+
+	gvmConfig, err := internal.LoadConfig()
+	if err != nil {
+		color.Red(err.Error())
+		os.Exit(1)
+	}
+
+	bootstrapVersion, _ := cmd.Flags().GetString("bootstrap")
+
+	var bootstrap *internal.DownloadVersion
+	if bootstrapVersion != "" {
+		bootstrap = gvmConfig.ResolveDownloadedVersion(bootstrapVersion)
+	} else {
+		// prefer the newest stable toolchain, the index is sorted newest first
+		for _, downloadedVersion := range *gvmConfig.GetDownloadedVersions() {
+			if internal.ValidateGoVersion(downloadedVersion.Version) && !internal.IsPrereleaseVersion(downloadedVersion.Version) {
+				bootstrap = &downloadedVersion
+				break
+			}
+		}
+		if bootstrap == nil {
+			for _, downloadedVersion := range gvmConfig.DownloadedVersions {
+				if !internal.IsDevelVersion(downloadedVersion.Version) {
+					bootstrap = &downloadedVersion
+					break
+				}
+			}
+		}
+	}
+
+	if bootstrap == nil {
+		color.Red("Build Error: no downloaded toolchain available for bootstrapping. Run 'gvm download <version>' first or pass --bootstrap")
+		os.Exit(1)
+	}
+
+	color.Blue(fmt.Sprintf("Preparing bootstrap toolchain %s", bootstrap.Version))
+	bootstrapRoot, err := bootstrap.GoRoot()
+	if err != nil {
+		color.Red(err.Error())
+		os.Exit(1)
+	}
+
+	color.Blue(fmt.Sprintf("Building Go from %s", source))
+	downloadVersion, err := internal.BuildGoFromSource(source, bootstrapRoot)
+	if err != nil {
+		color.Red(err.Error())
+		os.Exit(1)
+	}
+
+	if err := gvmConfig.MarkVersionAsDownloaded(&internal.RemoteVersion{Version: downloadVersion.Version, SHA256: downloadVersion.SHA256}, downloadVersion.TarPath); err != nil {
+		color.Red(err.Error())
+		os.Exit(1)
+	}
+
+	color.Green(fmt.Sprintf("Go %s was built from %s and saved in %s", downloadVersion.Version, source, downloadVersion.TarPath))
+}
+
+func init() {
+	installCmd.Flags().StringP("file", "f", "", "Path to a local Go release archive (.tar.gz or .zip)")
+	installCmd.Flags().String("sha256", "", "Expected sha256 checksum of the archive passed with --file")
+	installCmd.Flags().StringP("source", "s", "", "Build Go from a local git checkout or a branch, tag or commit of the go repository")
+	installCmd.Flags().String("signature", "", "Detached signature of the archive passed with --file (default <file>.minisig or <file>.sig)")
+	installCmd.Flags().Bool("require-signature", false, "Fail when the archive has no valid signature of a configured key")
+	installCmd.Flags().Bool("force", false, "Import an archive passed with --file even if it is built for another platform")
+	installCmd.Flags().Bool("frozen", false, "Install exactly the archives locked in gvm.lock, failing on any difference")
+	installCmd.Flags().String("bootstrap", "", "Downloaded Go version used as GOROOT_BOOTSTRAP for --source builds")
+	rootCmd.AddCommand(installCmd)
+}
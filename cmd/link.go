@@ -0,0 +1,91 @@
+/*
+Copyright © 2025 Syed Vilayat Ali Rizvi
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/vilayat-ali/gvm/internal"
+)
+
+// linkCmd represents the link command
+var linkCmd = &cobra.Command{
+	Use:   "link <name> <path>",
+	Short: "Register an externally installed Go as a managed version",
+	Long: `Register a Go installation that was not installed by gvm, e.g. one
+from a package manager, so it can be selected like any other version.
+
+The directory is validated by running its bin/go version. gvm only keeps a
+reference to the directory and never modifies or removes it.
+
+Examples:
+  gvm link system-1.22 /usr/lib/go-1.22
+  gvm use system-1.22`,
+	Args: cobra.ExactArgs(2),
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if !internal.ConfigExists() {
+			return fmt.Errorf("configuration not found. Please run 'gvm configure' first")
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		name, goroot := args[0], args[1]
+		force, _ := cmd.Flags().GetBool("force")
+
+		gvmConfig, err := internal.LoadConfig()
+		if err != nil {
+			color.Red(err.Error())
+			os.Exit(1)
+		}
+
+		if existing, exists := gvmConfig.DownloadedVersions[name]; exists && !force {
+			if existing.IsLink() {
+				color.Red(fmt.Sprintf("Link Error: '%s' is already linked to %s. Use --force to replace it.", name, existing.LinkPath))
+			} else {
+				color.Red(fmt.Sprintf("Link Error: '%s' is already a downloaded version. Use --force to replace it.", name))
+			}
+			os.Exit(1)
+		}
+
+		linkedVersion, goVersion, err := internal.NewLinkedVersion(name, goroot)
+		if err != nil {
+			color.Red(err.Error())
+			os.Exit(1)
+		}
+
+		if err := gvmConfig.AddDownloadedVersion(*linkedVersion); err != nil {
+			color.Red(err.Error())
+			os.Exit(1)
+		}
+
+		color.Green(fmt.Sprintf("✓ Linked %s (%s) as '%s'", linkedVersion.LinkPath, goVersion, name))
+		color.Cyan(fmt.Sprintf("Run 'gvm use %s' to switch to it", name))
+	},
+}
+
+func init() {
+	linkCmd.Flags().Bool("force", false, "Replace an existing version with the same name")
+	rootCmd.AddCommand(linkCmd)
+}
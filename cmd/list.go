@@ -25,6 +25,7 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
@@ -43,6 +44,7 @@ are set as the system default.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		showDownloaded, _ := cmd.Flags().GetBool("downloaded")
 		showCurrent, _ := cmd.Flags().GetBool("current")
+		showLong, _ := cmd.Flags().GetBool("long")
 
 		if showDownloaded {
 			gvmConfig, err := internal.LoadConfig()
@@ -51,7 +53,13 @@ are set as the system default.`,
 				os.Exit(1)
 			}
 
-			if len(gvmConfig.DownloadedVersions) == 0 {
+			orphans, err := gvmConfig.FindOrphanArchives()
+			if err != nil {
+				color.Red("✗ Error scanning downloaded archives: %s", err.Error())
+				os.Exit(1)
+			}
+
+			if len(gvmConfig.DownloadedVersions) == 0 && len(orphans) == 0 {
 				color.Yellow("📭 No downloaded Go versions found.")
 				color.Cyan("\nTry: gvm list      # to see available versions")
 				color.Cyan("     gvm install   # to install a version")
@@ -74,24 +82,36 @@ are set as the system default.`,
 				version := downloadVersion.Version
 				version_print_stmt := version
 
-				isReleaseCandidate := strings.Contains(version, "rc")
-				isCurrentVersion := version == *currentVersion
+				isReleaseCandidate := internal.IsPrereleaseVersion(version)
+				isCurrentVersion := internal.SameGoVersion(version, *currentVersion)
 
-				if !ltsFound && !isReleaseCandidate {
+				// devel builds and link names aren't releases
+				if !ltsFound && !isReleaseCandidate && !downloadVersion.IsLink() && internal.ValidateGoVersion(version) {
 					version_print_stmt += " 🏷️ LTS"
 					ltsFound = true
 				}
 
+				if downloadVersion.IsLink() {
+					version_print_stmt += fmt.Sprintf(" 🔗 %s", downloadVersion.LinkPath)
+				}
+
 				if isCurrentVersion {
 					version_print_stmt += " ✅"
 				}
 
+				isMissing := downloadVersion.IsMissing()
+				if isMissing {
+					version_print_stmt += " ⚠️ files missing"
+				}
+
 				bullet := "  • "
 				if isCurrentVersion {
 					bullet = "  ▶ "
 				}
 
-				if isCurrentVersion {
+				if isMissing {
+					color.New(color.FgRed).Printf("%s%s\n", bullet, version_print_stmt)
+				} else if isCurrentVersion {
 					color.New(color.FgGreen, color.Bold).Printf("%s%s\n", bullet, version_print_stmt)
 				} else if strings.Contains(version_print_stmt, "🏷️ LTS") {
 					color.New(color.FgCyan, color.Bold).Printf("%s%s\n", bullet, version_print_stmt)
@@ -100,25 +120,43 @@ are set as the system default.`,
 				} else {
 					color.New(color.FgMagenta).Printf("%s%s\n", bullet, version_print_stmt)
 				}
+
+				if showLong {
+					color.HiBlack("      installed %s · last used %s · used %d time(s)",
+						formatTimestamp(downloadVersion.InstalledAt),
+						formatTimestamp(downloadVersion.LastUsedAt),
+						downloadVersion.UseCount,
+					)
+				}
+			}
+
+			if len(orphans) > 0 {
+				fmt.Println()
+				color.Yellow("🗃️  Archives not known to gvm")
+				fmt.Println(strings.Repeat("─", 50))
+				for _, orphan := range orphans {
+					color.Yellow("  ? %s", orphan)
+				}
+				color.HiBlack("\n  Register them with 'gvm install --file <path>' or delete them.")
+			}
+
+			missingCount := 0
+			for _, downloadVersion := range gvmConfig.DownloadedVersions {
+				if downloadVersion.IsMissing() {
+					missingCount++
+				}
+			}
+			if missingCount > 0 {
+				color.HiBlack("  %d version(s) have missing files. Restore them with 'gvm download <version>' or 'gvm link --force'.", missingCount)
 			}
 
 			fmt.Println()
-			color.HiBlack("Legend: ✅ = Current | 🏷️ = LTS | • = Installed")
+			color.HiBlack("Legend: ✅ = Current | 🏷️ = LTS | 🔗 = Linked | ⚠️ = Files missing | ? = Unknown archive | • = Installed")
 			return
 		}
 
 		if showCurrent {
-			currentVersion, err := internal.GetCurrentGolangVersion()
-			if err != nil {
-				color.Red("✗ Error detecting current version: %s", err.Error())
-				os.Exit(1)
-			}
-
-			fmt.Println()
-			color.Cyan("⚡ Current Go Version")
-			fmt.Println(strings.Repeat("─", 30))
-			color.New(color.FgGreen, color.Bold).Printf("  %s\n", *currentVersion)
-			fmt.Println()
+			currentCmd.Run(cmd, args)
 			return
 		}
 
@@ -150,10 +188,10 @@ are set as the system default.`,
 		for _, remoteVersion := range config.AvailableVersions {
 			version_print_stmt := remoteVersion.Version
 
-			isReleaseCandidate := strings.Contains(remoteVersion.Version, "rc")
-			isCurrentVersion := remoteVersion.Version == *currentVersion
+			isReleaseCandidate := internal.IsPrereleaseVersion(remoteVersion.Version)
+			isCurrentVersion := internal.SameGoVersion(remoteVersion.Version, *currentVersion)
 
-			if !ltsFound && !isReleaseCandidate {
+			if !ltsFound && !isReleaseCandidate && internal.ValidateGoVersion(remoteVersion.Version) {
 				version_print_stmt += " 🏷️ LTS"
 				ltsFound = true
 			}
@@ -193,7 +231,7 @@ are set as the system default.`,
 		fmt.Println()
 		color.Cyan("💡 Tips:")
 		color.Cyan("  • Use 'gvm list -d' to see downloaded versions")
-		color.Cyan("  • Use 'gvm list -c' to see current version only")
+		color.Cyan("  • Use 'gvm current --source' to see the active version and why")
 		color.Cyan("  • Use 'gvm list update' to refresh available versions")
 	},
 }
@@ -215,15 +253,15 @@ This command updates the available list of all Go versions that can be downloade
 			os.Exit(1)
 		}
 
-		color.Blue("  Fetching latest versions from GitHub...")
-		if err := config.UpdateAvailableVersions(); err != nil {
-			color.Red("✗ Failed to update versions: %s", err.Error())
+		source, err := config.GetVersionSource()
+		if err != nil {
+			color.Red("✗ %s", err.Error())
 			os.Exit(1)
 		}
 
-		color.Blue("  Saving updated list...")
-		if err := config.Save(); err != nil {
-			color.Red("✗ Failed to save configuration: %s", err.Error())
+		color.Blue("  Fetching latest versions from %s...", source.Name())
+		if err := config.UpdateAvailableVersionsFrom(source); err != nil {
+			color.Red("✗ Failed to update versions: %s", err.Error())
 			os.Exit(1)
 		}
 
@@ -241,4 +279,14 @@ func init() {
 	// Define flags for the list command
 	listCmd.Flags().BoolP("downloaded", "d", false, "Show downloaded versions only")
 	listCmd.Flags().BoolP("current", "c", false, "Show current active version only")
+	listCmd.Flags().MarkDeprecated("current", "use 'gvm current' instead")
+	listCmd.Flags().BoolP("long", "l", false, "Show install time and usage stats of downloaded versions")
+}
+
+// Formats a unix milliseconds timestamp, "never" when unset.
+func formatTimestamp(unixMilli int64) string {
+	if unixMilli == 0 {
+		return "never"
+	}
+	return time.UnixMilli(unixMilli).Format("2006-01-02 15:04")
 }
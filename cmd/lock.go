@@ -0,0 +1,106 @@
+/*
+Copyright © 2025 Syed Vilayat Ali Rizvi
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/vilayat-ali/gvm/internal"
+)
+
+// lockCmd represents the lock command
+var lockCmd = &cobra.Command{
+	Use:   "lock",
+	Short: "Record the exact toolchains of the project in gvm.lock",
+	Long: `Resolve the toolchains of the nearest gvm.toml and the version pinned by
+the nearest .go-version or go.mod to exact releases and record the archive
+url and sha256 of every platform into gvm.lock.
+
+Version pins without a patch number (e.g. "1.25") and go.mod versions
+resolve to the newest release of their minor line, just like the shims do.
+Platforms default to those of gvm.toml, or the running platform. Locking
+needs a version source publishing checksums (go.dev, file or mirror).
+
+Commit gvm.lock and install with 'gvm install --frozen' to get exactly the
+locked archives. The install fails if anything differs, e.g. a mirror
+serving another file.
+
+Examples:
+  gvm lock
+  gvm lock --platform linux/amd64 --platform darwin/arm64`,
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if !internal.ConfigExists() {
+			return fmt.Errorf("configuration not found. Please run 'gvm configure' first")
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		platforms, _ := cmd.Flags().GetStringArray("platform")
+
+		cwd, err := os.Getwd()
+		if err != nil {
+			color.Red(err.Error())
+			os.Exit(1)
+		}
+
+		requests, lockPath, err := internal.ProjectLockRequests(cwd, platforms)
+		if err != nil {
+			color.Red(err.Error())
+			os.Exit(1)
+		}
+
+		gvmConfig, err := internal.LoadConfig()
+		if err != nil {
+			color.Red("✗ Error loading configuration: %s", err.Error())
+			os.Exit(1)
+		}
+
+		lockfile, err := gvmConfig.ResolveLock(requests, lockPath)
+		if err != nil {
+			color.Red(err.Error())
+			os.Exit(1)
+		}
+
+		if err := lockfile.Save(); err != nil {
+			color.Red(err.Error())
+			os.Exit(1)
+		}
+
+		fmt.Println()
+		color.Cyan("🔒 %s", lockfile.Path)
+		fmt.Println(strings.Repeat("─", 60))
+		for _, toolchain := range lockfile.Toolchains {
+			color.Green("  %-16s %-10s → %s", toolchain.Name, toolchain.Requested, toolchain.Version)
+			color.HiBlack("      %s", strings.Join(toolchain.Platforms(), ", "))
+		}
+		color.Green("\n✓ Locked %d toolchain(s)", len(lockfile.Toolchains))
+	},
+}
+
+func init() {
+	lockCmd.Flags().StringArray("platform", nil, "Platform (goos/goarch) to lock toolchains without platforms in gvm.toml for, repeatable")
+	rootCmd.AddCommand(lockCmd)
+}
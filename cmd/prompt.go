@@ -0,0 +1,100 @@
+/*
+Copyright © 2025 Syed Vilayat Ali Rizvi
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/mattn/go-isatty"
+	"github.com/spf13/cobra"
+	"github.com/vilayat-ali/gvm/internal"
+)
+
+// promptCmd represents the prompt command
+var promptCmd = &cobra.Command{
+	Use:   "prompt",
+	Short: "Print the active Go version for shell prompts",
+	Long: `Print the Go version active in the current directory for PS1, starship,
+tmux and similar. It reads only the shim index and version files, never the
+complete config or the network, so it returns within a few milliseconds.
+
+Nothing is printed outside a Go project (no go.mod or .go-version in the
+directory or its parents) unless --always is passed, and errors are silent.
+
+Placeholders of --format:
+  {version}   release name, e.g. go1.25.5
+  {number}    version number, e.g. 1.25.5
+  {source}    file or variable that selected the version, e.g. .go-version
+  {missing}   "!" when the selected version isn't installed, else empty
+
+Examples:
+  gvm prompt                              # go 1.25.5
+  gvm prompt --format '🐹 {number}{missing}'
+  gvm prompt --json
+  PS1='$(gvm prompt --format "({number}) ")\$ '`,
+	Run: func(cmd *cobra.Command, args []string) {
+		format, _ := cmd.Flags().GetString("format")
+		always, _ := cmd.Flags().GetBool("always")
+		asJSON, _ := cmd.Flags().GetBool("json")
+
+		dir, err := os.Getwd()
+		if err != nil {
+			return
+		}
+
+		inProject := internal.IsGoProjectDir(dir)
+		if !inProject && !always {
+			return
+		}
+
+		active, err := internal.ResolveActiveVersion(dir)
+		if err != nil || active == nil {
+			return
+		}
+
+		if asJSON {
+			data, err := json.Marshal(struct {
+				*internal.ActiveVersion
+				Number  string `json:"number"`
+				Project bool   `json:"project"`
+			}{active, active.Number(), inProject})
+			if err == nil {
+				fmt.Println(string(data))
+			}
+			return
+		}
+
+		fmt.Print(active.FormatPrompt(format))
+		if isatty.IsTerminal(os.Stdout.Fd()) {
+			fmt.Println()
+		}
+	},
+}
+
+func init() {
+	promptCmd.Flags().StringP("format", "f", "go {number}{missing}", "Output format, see the placeholders above")
+	promptCmd.Flags().Bool("always", false, "Print the version outside Go projects as well")
+	promptCmd.Flags().Bool("json", false, "Print the version, number, source, GOROOT and project state as JSON")
+	rootCmd.AddCommand(promptCmd)
+}
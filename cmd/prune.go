@@ -0,0 +1,183 @@
+/*
+Copyright © 2025 Syed Vilayat Ali Rizvi
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/vilayat-ali/gvm/internal"
+)
+
+// duCmd represents the du command
+var duCmd = &cobra.Command{
+	Use:   "du",
+	Short: "Show disk usage of downloaded Go versions",
+	Long: `Show the disk space taken by every downloaded Go version, split into its
+tarball and its extracted toolchain, followed by the size of the shared
+download cache.
+
+Tarballs are hardlinked from the download cache whenever possible, so the
+same bytes may be counted in both places.`,
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if !internal.ConfigExists() {
+			return fmt.Errorf("configuration not found. Please run 'gvm configure' first")
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		gvmConfig, err := internal.LoadConfig()
+		if err != nil {
+			color.Red("✗ Error loading configuration: %s", err.Error())
+			os.Exit(1)
+		}
+
+		fmt.Println()
+		color.Cyan("💾 Disk Usage")
+		fmt.Println(strings.Repeat("─", 60))
+		color.HiBlack("  %-20s %10s %10s %10s", "VERSION", "TARBALL", "EXTRACTED", "TOTAL")
+
+		var total int64
+		for _, usage := range gvmConfig.GetDiskUsage() {
+			total += usage.Total()
+			name := usage.Version.Version
+			if usage.Version.IsLink() {
+				name += " 🔗"
+			}
+			color.New(color.FgMagenta).Printf("  %-20s %10s %10s %10s\n",
+				name,
+				internal.FormatBytes(usage.ArchiveSize),
+				internal.FormatBytes(usage.ExtractedSize),
+				internal.FormatBytes(usage.Total()),
+			)
+		}
+
+		fmt.Println(strings.Repeat("─", 60))
+		color.Cyan("  %-20s %32s", "versions", internal.FormatBytes(total))
+
+		if entries, err := internal.ListCache(); err == nil {
+			var cacheSize int64
+			for _, entry := range entries {
+				cacheSize += entry.Size
+			}
+			color.Cyan("  %-20s %32s", "download cache", internal.FormatBytes(cacheSize))
+		}
+		fmt.Println()
+	},
+}
+
+// pruneCmd represents the prune command
+var pruneCmd = &cobra.Command{
+	Use:   "prune",
+	Short: "Remove downloaded Go versions you no longer need",
+	Long: `Remove downloaded Go versions selected by one or more policies.
+
+The active and the default version are never removed, neither are linked
+installations. Removing a version deletes its tarball and extracted
+toolchain, the shared download cache is pruned with 'gvm cache prune'.
+
+Examples:
+  gvm prune --keep-patches 1 --dry-run   # keep only the newest patch per minor
+  gvm prune --remove-unsupported         # drop minors older than the two supported ones
+  gvm prune --unused-for 60d`,
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if !internal.ConfigExists() {
+			return fmt.Errorf("configuration not found. Please run 'gvm configure' first")
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		keepPatches, _ := cmd.Flags().GetInt("keep-patches")
+		removeUnsupported, _ := cmd.Flags().GetBool("remove-unsupported")
+		unusedForFlag, _ := cmd.Flags().GetString("unused-for")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+		policy := internal.PrunePolicy{
+			KeepPatches:       keepPatches,
+			RemoveUnsupported: removeUnsupported,
+		}
+
+		if unusedForFlag != "" {
+			unusedFor, err := internal.ParseAge(unusedForFlag)
+			if err != nil {
+				color.Red(err.Error())
+				os.Exit(1)
+			}
+			policy.UnusedFor = unusedFor
+		}
+
+		if policy.KeepPatches <= 0 && !policy.RemoveUnsupported && policy.UnusedFor == 0 {
+			color.Red("Arg Error: Expected at least one of --keep-patches, --remove-unsupported or --unused-for")
+			os.Exit(1)
+		}
+
+		gvmConfig, err := internal.LoadConfig()
+		if err != nil {
+			color.Red("✗ Error loading configuration: %s", err.Error())
+			os.Exit(1)
+		}
+
+		protected := []string{gvmConfig.DefaultVersion}
+		if currentVersion, err := internal.GetCurrentGolangVersion(); err == nil {
+			protected = append(protected, *currentVersion)
+		}
+
+		candidates := gvmConfig.SelectPruneCandidates(policy, protected, time.Now())
+		if len(candidates) == 0 {
+			color.Green("✓ Nothing to prune")
+			return
+		}
+
+		for _, candidate := range candidates {
+			if dryRun {
+				color.Yellow("  would remove %s (%s)", candidate.Version.Version, candidate.Reason)
+				continue
+			}
+
+			if err := gvmConfig.RemoveDownloadedVersion(candidate.Version.Version); err != nil {
+				color.Red("✗ %s", err.Error())
+				os.Exit(1)
+			}
+			color.Yellow("  removed %s (%s)", candidate.Version.Version, candidate.Reason)
+		}
+
+		if dryRun {
+			color.Cyan("\nDry run: %d version(s) would be removed", len(candidates))
+		} else {
+			color.Green("\n✓ Removed %d version(s)", len(candidates))
+		}
+	},
+}
+
+func init() {
+	pruneCmd.Flags().Int("keep-patches", 0, "Keep only the newest N patch releases of every minor")
+	pruneCmd.Flags().Bool("remove-unsupported", false, "Remove minors older than the supported releases")
+	pruneCmd.Flags().String("unused-for", "", "Remove versions unused for longer than this (e.g., 60d)")
+	pruneCmd.Flags().Bool("dry-run", false, "Only show what would be removed")
+
+	rootCmd.AddCommand(duCmd)
+	rootCmd.AddCommand(pruneCmd)
+}
@@ -25,7 +25,10 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/fatih/color"
+	"github.com/mattn/go-isatty"
 	"github.com/spf13/cobra"
+	"github.com/vilayat-ali/gvm/internal"
 )
 
 const version = "1.2.0"
@@ -52,9 +55,15 @@ Examples:
   gvm list-remote           # List all available versions
   gvm default 1.20.3        # Set Go 1.20.3 as default
 
+Notices about newer patch releases are disabled with "disable_update_notice"
+in ~/.config/gvm/config.json or the GVM_NO_UPDATE_NOTICE environment variable.
+
 Documentation: https://vilayat-ali.github.io/gvm
 Source Code:   https://github.com/vilayat-ali/gvm`,
 	Version: version,
+	PersistentPostRun: func(cmd *cobra.Command, args []string) {
+		printUpdateNotice(cmd)
+	},
 	Run: func(cmd *cobra.Command, args []string) {
 		// Show help if no arguments provided
 		if len(args) == 0 {
@@ -76,6 +85,53 @@ func Execute() {
 	}
 }
 
+// Commands which either report versions themselves or shouldn't be
+// followed by the update notice.
+var updateNoticeSkipped = map[string]bool{
+	"upgrade":    true,
+	"update":     true,
+	"configure":  true,
+	"completion": true,
+	"help":       true,
+	"prompt":     true,
+	"shim-exec":  true,
+}
+
+// Prints a notice about a newer patch release of the active version to
+// stderr. Only the cached remote index is consulted so no network
+// latency is added, and nothing is printed for machine readable output.
+func printUpdateNotice(cmd *cobra.Command) {
+	if updateNoticeSkipped[cmd.Name()] || isJSONOutput(cmd) || !isatty.IsTerminal(os.Stderr.Fd()) || os.Getenv("GVM_NO_UPDATE_NOTICE") != "" {
+		return
+	}
+
+	if !internal.ConfigExists() {
+		return
+	}
+
+	gvmConfig, err := internal.LoadConfig()
+	if err != nil || gvmConfig.DisableUpdateNotice {
+		return
+	}
+
+	currentVersion, err := internal.GetCurrentGolangVersion()
+	if err != nil {
+		return
+	}
+
+	if notice := gvmConfig.UpdateNotice(*currentVersion); notice != "" {
+		fmt.Fprintln(os.Stderr)
+		color.New(color.FgHiBlack).Fprintln(os.Stderr, "💡 "+notice)
+	}
+}
+
+// Reports whether the command was asked for JSON output through its
+// own --json flag.
+func isJSONOutput(cmd *cobra.Command) bool {
+	flag := cmd.Flags().Lookup("json")
+	return flag != nil && flag.Value.String() == "true"
+}
+
 func init() {
 	// Global flags
 	rootCmd.PersistentFlags().BoolP("verbose", "v", false, "verbose output")
@@ -0,0 +1,107 @@
+/*
+Copyright © 2025 Syed Vilayat Ali Rizvi
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/vilayat-ali/gvm/internal"
+)
+
+// selfUpdateCmd represents the self-update command
+var selfUpdateCmd = &cobra.Command{
+	Use:   "self-update",
+	Short: "Update gvm itself to the newest release",
+	Long: `Check the gvm release feed for a newer release, download the archive for this
+platform, verify it against the published SHA256SUMS.txt and atomically replace
+the running gvm executable. When "signature_keys" are configured the detached
+signature of SHA256SUMS.txt is verified like the one of Go archives.
+
+The feed defaults to the github releases of gvm and can be changed with
+"self_update_url" in the config or --url, e.g. to test against a local server.
+
+Examples:
+  gvm self-update
+  gvm self-update --check
+  gvm self-update --channel beta
+  gvm self-update --url http://localhost:8080/releases.json`,
+	Run: func(cmd *cobra.Command, args []string) {
+		channel, _ := cmd.Flags().GetString("channel")
+		feedURL, _ := cmd.Flags().GetString("url")
+		checkOnly, _ := cmd.Flags().GetBool("check")
+		force, _ := cmd.Flags().GetBool("force")
+
+		// the config is optional, gvm must be able to update before it is configured
+		gvmConfig := &internal.Config{}
+		if internal.ConfigExists() {
+			if loaded, err := internal.LoadConfig(); err == nil {
+				gvmConfig = loaded
+			}
+		}
+		if feedURL == "" {
+			feedURL = gvmConfig.SelfUpdateURL
+		}
+
+		color.Blue("Checking for gvm updates...")
+		release, err := internal.FindSelfRelease(feedURL, channel)
+		if err != nil {
+			color.Red(err.Error())
+			os.Exit(1)
+		}
+
+		if !force && !internal.IsNewerSelfVersion(version, release.Version) {
+			color.Green(fmt.Sprintf("✓ gvm v%s is up to date", version))
+			return
+		}
+
+		color.Cyan(fmt.Sprintf("gvm v%s is available (you're on v%s)", release.Version, version))
+		if checkOnly {
+			return
+		}
+
+		binaryPath, tmpDir, signed, err := release.Download(gvmConfig)
+		if err != nil {
+			color.Red(err.Error())
+			os.Exit(1)
+		}
+		defer os.RemoveAll(tmpDir)
+		reportSignature(gvmConfig, "SHA256SUMS.txt", signed)
+
+		if err := internal.ReplaceExecutable(binaryPath); err != nil {
+			color.Red(err.Error())
+			os.Exit(1)
+		}
+
+		color.Green(fmt.Sprintf("✓ Updated gvm to v%s", release.Version))
+	},
+}
+
+func init() {
+	selfUpdateCmd.Flags().String("channel", internal.SelfUpdateChannelStable, "Release channel (stable or beta)")
+	selfUpdateCmd.Flags().String("url", "", "Release feed url, overrides the configured one")
+	selfUpdateCmd.Flags().Bool("check", false, "Only check whether an update is available")
+	selfUpdateCmd.Flags().Bool("force", false, "Reinstall even if the newest release is already installed")
+	rootCmd.AddCommand(selfUpdateCmd)
+}
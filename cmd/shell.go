@@ -0,0 +1,204 @@
+/*
+Copyright © 2025 Syed Vilayat Ali Rizvi
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/mattn/go-isatty"
+	"github.com/spf13/cobra"
+	"github.com/vilayat-ali/gvm/internal"
+)
+
+// shellCmd represents the shell command
+var shellCmd = &cobra.Command{
+	Use:   "shell [version]",
+	Short: "Use a Go version in the current shell session only",
+	Long: `Select a Go version for the current shell session through the
+GVM_GO_VERSION environment variable, which the gvm shims (see 'gvm rehash')
+honor before any .go-version, go.mod or default version. Other terminals,
+the project settings and the default version are not touched.
+
+A program can't change the environment of its shell, so gvm shell prints the
+shell code to evaluate. Install the shell hook once to run 'gvm shell'
+directly:
+  echo 'eval "$(gvm shell --hook)"' >> ~/.bashrc    # or ~/.zshrc
+  echo 'gvm shell --hook fish | source' >> ~/.config/fish/config.fish
+  Add-Content $PROFILE 'gvm shell --hook pwsh | Out-String | Invoke-Expression'
+
+Without a version the session version is printed.
+
+Examples:
+  gvm shell 1.23.9
+  gvm shell --unset
+  eval "$(gvm shell 1.23.9)"   # without the hook`,
+	Run: func(cmd *cobra.Command, args []string) {
+		unset, _ := cmd.Flags().GetBool("unset")
+		hook, _ := cmd.Flags().GetBool("hook")
+		shell, _ := cmd.Flags().GetString("shell")
+
+		if shell == "" && hook && len(args) == 1 {
+			shell, args = args[0], nil
+		}
+		if shell == "" {
+			shell = filepath.Base(os.Getenv("SHELL"))
+		}
+
+		// stdout is evaluated by the shell, messages go to stderr
+		stderr := func(attribute color.Attribute, format string, a ...any) {
+			color.New(attribute).Fprintf(os.Stderr, format+"\n", a...)
+		}
+
+		if hook {
+			fmt.Print(shellHook(shell))
+			return
+		}
+
+		if unset {
+			fmt.Println(shellUnsetEnv(shell, internal.VersionEnvVar))
+			warnNotEvaluated(stderr, "--unset")
+			return
+		}
+
+		if len(args) == 0 {
+			if version := os.Getenv(internal.VersionEnvVar); version != "" {
+				stderr(color.FgGreen, "%s (set by %s)", version, internal.VersionEnvVar)
+			} else {
+				stderr(color.FgYellow, "No session version set. Run 'gvm shell <version>' to set one.")
+			}
+			return
+		}
+
+		if !internal.ConfigExists() {
+			stderr(color.FgRed, "configuration not found. Please run 'gvm configure' first")
+			os.Exit(1)
+		}
+
+		gvmConfig, err := internal.LoadConfig()
+		if err != nil {
+			stderr(color.FgRed, "%s", err.Error())
+			os.Exit(1)
+		}
+
+		downloadVersion := gvmConfig.ResolveDownloadedVersion(args[0])
+		if downloadVersion == nil {
+			stderr(color.FgRed, "Input Error: Go version %s is not downloaded. Run 'gvm download %s' first", args[0], args[0])
+			os.Exit(1)
+		}
+
+		fmt.Println(shellSetEnv(shell, internal.VersionEnvVar, downloadVersion.Version))
+		internal.JournalVersionUsage(downloadVersion.Version, time.Now())
+		warnNotEvaluated(stderr, args[0])
+
+		if shimsDir, err := internal.ShimsDir(); err == nil && !pathContains(os.Getenv("PATH"), shimsDir) {
+			stderr(color.FgCyan, "The session version is applied by the gvm shims. Run 'gvm rehash' and add %s to your PATH.", shimsDir)
+		}
+	},
+}
+
+// Explains how to apply the printed shell code when gvm shell was run
+// directly in a terminal instead of through the hook or eval.
+func warnNotEvaluated(stderr func(color.Attribute, string, ...any), arg string) {
+	if !isatty.IsTerminal(os.Stdout.Fd()) {
+		return
+	}
+	stderr(color.FgYellow, "\nThe output above has to be evaluated by your shell: eval \"$(gvm shell %s)\"", arg)
+	stderr(color.FgYellow, "Install the shell hook to skip this step: eval \"$(gvm shell --hook)\"")
+}
+
+func shellSetEnv(shell string, name string, value string) string {
+	switch shell {
+	case "fish":
+		return fmt.Sprintf("set -gx %s %s", name, shellQuote(shell, value))
+	case "pwsh", "powershell":
+		return fmt.Sprintf("$Env:%s = %s", name, shellQuote(shell, value))
+	default:
+		return fmt.Sprintf("export %s=%s", name, shellQuote(shell, value))
+	}
+}
+
+func shellUnsetEnv(shell string, name string) string {
+	switch shell {
+	case "fish":
+		return fmt.Sprintf("set -e %s", name)
+	case "pwsh", "powershell":
+		return fmt.Sprintf("Remove-Item Env:%s -ErrorAction SilentlyContinue", name)
+	default:
+		return fmt.Sprintf("unset %s", name)
+	}
+}
+
+// Single quotes a value for the shell.
+func shellQuote(shell string, value string) string {
+	if shell == "pwsh" || shell == "powershell" {
+		return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+	}
+	return "'" + strings.ReplaceAll(value, "'", `'"'"'`) + "'"
+}
+
+// Returns a gvm wrapper function evaluating the output of 'gvm shell'.
+func shellHook(shell string) string {
+	switch shell {
+	case "fish":
+		return `function gvm
+    if test "$argv[1]" = shell
+        command gvm $argv | source
+    else
+        command gvm $argv
+    end
+end
+`
+	case "pwsh", "powershell":
+		return `function gvm {
+    $gvm = Get-Command gvm -CommandType Application | Select-Object -First 1
+    if ($args.Count -gt 0 -and $args[0] -eq 'shell') {
+        & $gvm @args | Out-String | Invoke-Expression
+    } else {
+        & $gvm @args
+    }
+}
+`
+	default:
+		return `gvm() {
+  if [ "$1" = "shell" ]; then
+    eval "$(command gvm "$@")"
+  else
+    command gvm "$@"
+  fi
+}
+`
+	}
+}
+
+func init() {
+	shellCmd.Flags().Bool("unset", false, "Remove the session version")
+	shellCmd.Flags().Bool("hook", false, "Print the shell hook running 'gvm shell' without eval")
+	shellCmd.Flags().String("shell", "", "Shell to print code for: bash, zsh, fish or pwsh (defaults to $SHELL)")
+	// stdout is evaluated by the shell hook, keep help and usage out of it
+	shellCmd.SetOut(os.Stderr)
+	rootCmd.AddCommand(shellCmd)
+}
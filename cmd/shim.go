@@ -0,0 +1,163 @@
+/*
+Copyright © 2025 Syed Vilayat Ali Rizvi
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/vilayat-ali/gvm/internal"
+)
+
+// rehashCmd represents the rehash command
+var rehashCmd = &cobra.Command{
+	Use:   "rehash",
+	Short: "Regenerate the go and gofmt shims",
+	Long: `Regenerate the shims in ~/.gvm/shims.
+
+With the shims directory first in PATH, every call of go, gofmt or another
+toolchain binary runs the Go version selected for the current directory, in
+order of precedence from:
+  1. the GVM_GO_VERSION environment variable
+  2. the nearest .go-version file
+  3. the nearest go.mod (toolchain directive, then go directive)
+  4. the default version set with 'gvm use'
+
+Run it again after installing a toolchain that ships new binaries.
+
+Examples:
+  gvm rehash
+  export PATH="$HOME/.gvm/shims:$PATH"`,
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if !internal.ConfigExists() {
+			return fmt.Errorf("configuration not found. Please run 'gvm configure' first")
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		gvmConfig, err := internal.LoadConfig()
+		if err != nil {
+			color.Red(err.Error())
+			os.Exit(1)
+		}
+
+		if err := gvmConfig.WriteShimIndex(); err != nil {
+			color.Red(err.Error())
+			os.Exit(1)
+		}
+
+		executable, err := os.Executable()
+		if err != nil {
+			color.Red("Shim Error: failed to locate the gvm executable: %s", err.Error())
+			os.Exit(1)
+		}
+		if resolved, err := filepath.EvalSymlinks(executable); err == nil {
+			executable = resolved
+		}
+
+		tools, err := internal.RehashShims(gvmConfig.ShimIndex(), executable)
+		if err != nil {
+			color.Red(err.Error())
+			os.Exit(1)
+		}
+
+		shimsDir, _ := internal.ShimsDir()
+		color.Green(fmt.Sprintf("✓ Generated %d shim(s) in %s: %s", len(tools), shimsDir, strings.Join(tools, ", ")))
+
+		if !pathContains(os.Getenv("PATH"), shimsDir) {
+			color.Cyan("\nAdd the shims to your PATH, e.g. in ~/.bashrc or ~/.zshrc:")
+			color.Cyan(fmt.Sprintf("  export PATH=\"%s:$PATH\"", shimsDir))
+		}
+	},
+}
+
+// shimExecCmd is run by the shims, it resolves the Go version for the
+// working directory and runs the requested tool of that toolchain.
+var shimExecCmd = &cobra.Command{
+	Use:                "shim-exec <tool> [args]",
+	Hidden:             true,
+	DisableFlagParsing: true,
+	Args:               cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		tool := args[0]
+
+		// stdout belongs to the tool, all gvm messages go to stderr
+		fail := func(format string, a ...any) {
+			fmt.Fprintf(os.Stderr, "gvm: "+format+"\n", a...)
+			os.Exit(1)
+		}
+
+		index, err := internal.LoadShimIndex()
+		if err != nil {
+			fail("%s", err.Error())
+		}
+
+		dir, err := os.Getwd()
+		if err != nil {
+			fail("%s", err.Error())
+		}
+
+		request := internal.ResolveVersionRequest(dir, index.Default)
+		if request == nil {
+			fail("no Go version selected. Run 'gvm use <version>' or create a %s file", internal.GoVersionFile)
+		}
+
+		name, goroot, ok := index.Resolve(request)
+		if !ok {
+			fail("Go %s requested by %s is not installed. Run 'gvm install %s'", request.Version, request.Source, request.Version)
+		}
+
+		goroot, err = internal.PrepareGoRoot(name, goroot)
+		if err != nil {
+			fail("%s", err.Error())
+		}
+
+		// best effort, usage stats must never break the tool
+		internal.JournalVersionUsage(name, time.Now())
+
+		exitCode, err := internal.RunToolchainTool(goroot, tool, args[1:])
+		if err != nil {
+			fail("%s", err.Error())
+		}
+		os.Exit(exitCode)
+	},
+}
+
+// Reports whether dir is an entry of the PATH list.
+func pathContains(pathList string, dir string) bool {
+	for _, entry := range filepath.SplitList(pathList) {
+		if filepath.Clean(entry) == filepath.Clean(dir) {
+			return true
+		}
+	}
+	return false
+}
+
+func init() {
+	rootCmd.AddCommand(rehashCmd)
+	rootCmd.AddCommand(shimExecCmd)
+}
@@ -0,0 +1,224 @@
+/*
+Copyright © 2025 Syed Vilayat Ali Rizvi
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/vilayat-ali/gvm/internal"
+)
+
+// syncCmd represents the sync command
+var syncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Install the toolchains listed in gvm.toml",
+	Long: `Install exactly the toolchains a project's gvm.toml manifest requires on
+this platform and report how the installed versions drift from it.
+
+gvm.toml is looked up from the current directory upwards:
+
+  platforms = ["linux/amd64", "darwin/arm64"]
+
+  [toolchains.main]
+  version = "1.25.5"
+
+  [toolchains.legacy]
+  version = "1.21.13"
+  platforms = ["linux/amd64"]
+  sha256 = { "linux/amd64" = "<sha256 of go1.21.13.linux-amd64.tar.gz>" }
+
+Missing toolchains are downloaded in parallel. Every archive is checked
+against the pinned checksum, the version it contains and the configured
+signature keys. Installed archives with a different checksum are reported
+but never replaced, installed versions missing from the manifest are only
+reported.
+
+Examples:
+  gvm sync
+  gvm sync --check          # only report drift, exit 1 when there is any
+  gvm sync --jobs 2 --file ci/gvm.toml`,
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if !internal.ConfigExists() {
+			return fmt.Errorf("configuration not found. Please run 'gvm configure' first")
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		checkOnly, _ := cmd.Flags().GetBool("check")
+		jobs, _ := cmd.Flags().GetInt("jobs")
+		requireSignature, _ := cmd.Flags().GetBool("require-signature")
+
+		manifest := loadManifest(cmd)
+
+		gvmConfig, err := internal.LoadConfig()
+		if err != nil {
+			color.Red("✗ Error loading configuration: %s", err.Error())
+			os.Exit(1)
+		}
+
+		platform := internal.CurrentPlatform()
+		fmt.Println()
+		color.Cyan("📋 %s for %s", manifest.Path, platform)
+		fmt.Println(strings.Repeat("─", 60))
+
+		for _, toolchain := range manifest.Toolchains {
+			if !toolchain.HasPlatform(platform) {
+				color.HiBlack("  - %-16s %-12s not needed on %s", toolchain.Name, toolchain.Version, platform)
+			}
+		}
+
+		drifts := gvmConfig.ManifestDrift(manifest)
+		failed := false
+		missing := make([]internal.ManifestToolchain, 0)
+		queued := make(map[string]bool)
+
+		for _, toolchain := range manifest.ToolchainsFor(platform) {
+			drift := findDrift(drifts, toolchain.Name)
+			switch {
+			case drift == nil:
+				color.Green("  ✓ %-16s %-12s installed", toolchain.Name, toolchain.Version)
+			case drift.Kind == internal.DriftChecksum:
+				color.Red("  ✗ %-16s %-12s %s", toolchain.Name, toolchain.Version, drift.Detail)
+				failed = true
+			default:
+				color.Yellow("  ↓ %-16s %-12s %s", toolchain.Name, toolchain.Version, drift.Detail)
+				if !queued[toolchain.Version] {
+					queued[toolchain.Version] = true
+					missing = append(missing, toolchain)
+				}
+			}
+		}
+
+		if checkOnly {
+			printExtraVersions(drifts)
+			if failed || len(missing) > 0 {
+				color.Red("\n✗ Installed toolchains differ from %s, run 'gvm sync'", filepath.Base(manifest.Path))
+				os.Exit(1)
+			}
+			color.Green("\n✓ Installed toolchains match %s", filepath.Base(manifest.Path))
+			return
+		}
+
+		if len(missing) > 0 {
+			jobs = max(1, min(jobs, len(missing)))
+			color.Cyan("\n⬇️  Downloading %d toolchain(s) with %d parallel job(s)...", len(missing), jobs)
+
+			for _, result := range gvmConfig.SyncManifestToolchains(missing, jobs, requireSignature) {
+				if result.Err != nil {
+					color.Red("  ✗ %-16s %-12s %s", result.Toolchain.Name, result.Toolchain.Version, result.Err.Error())
+					failed = true
+					continue
+				}
+
+				// replace entries whose files went missing
+				if downloaded := gvmConfig.ResolveDownloadedVersion(result.Remote.Version); downloaded != nil {
+					delete(gvmConfig.DownloadedVersions, downloaded.Version)
+				}
+				if err := gvmConfig.MarkVersionAsDownloaded(result.Remote, result.ArchivePath); err != nil {
+					color.Red("  ✗ %-16s %-12s %s", result.Toolchain.Name, result.Toolchain.Version, err.Error())
+					failed = true
+					continue
+				}
+
+				status := "installed"
+				if result.Signed {
+					status += ", signature verified"
+				} else if len(gvmConfig.SignatureKeys) > 0 {
+					status += ", not signed"
+				}
+				color.Green("  ✓ %-16s %-12s %s", result.Toolchain.Name, result.Remote.Version, status)
+			}
+		}
+
+		printExtraVersions(drifts)
+
+		if failed {
+			color.Red("\n✗ Some toolchains could not be synced")
+			os.Exit(1)
+		}
+		color.Green("\n✓ Toolchains are in sync with %s", filepath.Base(manifest.Path))
+	},
+}
+
+// Loads the manifest given with --file or the nearest gvm.toml.
+func loadManifest(cmd *cobra.Command) *internal.Manifest {
+	path, _ := cmd.Flags().GetString("file")
+	if path == "" {
+		cwd, err := os.Getwd()
+		if err != nil {
+			color.Red(err.Error())
+			os.Exit(1)
+		}
+
+		found, ok := internal.FindManifest(cwd)
+		if !ok {
+			color.Red("Manifest Error: no %s found in %s or its parents", internal.ManifestFileName, cwd)
+			os.Exit(1)
+		}
+		path = found
+	}
+
+	manifest, err := internal.LoadManifest(path)
+	if err != nil {
+		color.Red(err.Error())
+		os.Exit(1)
+	}
+
+	return manifest
+}
+
+func findDrift(drifts []internal.ManifestDrift, toolchain string) *internal.ManifestDrift {
+	for _, drift := range drifts {
+		if drift.Toolchain == toolchain && drift.IsProblem() {
+			return &drift
+		}
+	}
+	return nil
+}
+
+func printExtraVersions(drifts []internal.ManifestDrift) {
+	header := false
+	for _, drift := range drifts {
+		if drift.Kind != internal.DriftExtra {
+			continue
+		}
+		if !header {
+			fmt.Println()
+			color.HiBlack("Installed but not listed in %s:", internal.ManifestFileName)
+			header = true
+		}
+		color.HiBlack("  + %s", drift.Version)
+	}
+}
+
+func init() {
+	syncCmd.Flags().StringP("file", "f", "", "Manifest to sync instead of the nearest gvm.toml")
+	syncCmd.Flags().IntP("jobs", "j", 4, "Number of parallel downloads")
+	syncCmd.Flags().Bool("check", false, "Only report drift, exit 1 when toolchains are missing or differ")
+	syncCmd.Flags().Bool("require-signature", false, "Fail when an archive has no valid signature of a configured key")
+	rootCmd.AddCommand(syncCmd)
+}
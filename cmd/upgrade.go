@@ -0,0 +1,127 @@
+/*
+Copyright © 2025 Syed Vilayat Ali Rizvi
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in
+all copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN
+THE SOFTWARE.
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+	"github.com/vilayat-ali/gvm/internal"
+)
+
+// upgradeCmd represents the upgrade command
+var upgradeCmd = &cobra.Command{
+	Use:   "upgrade",
+	Short: "Move to the newest patch release of the current Go minor",
+	Long: `Upgrade the active Go version to the newest patch release of its minor,
+e.g. from go1.25.5 to go1.25.6.
+
+The remote version list is refreshed first, the new release is downloaded and
+verified, and then selected like 'gvm use' would.
+
+Examples:
+  gvm upgrade
+  gvm upgrade --prune-old   # also remove the previous patch release`,
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if !internal.ConfigExists() {
+			return fmt.Errorf("configuration not found. Please run 'gvm configure' first")
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		pruneOld, _ := cmd.Flags().GetBool("prune-old")
+
+		currentVersion, err := internal.GetCurrentGolangVersion()
+		if err != nil {
+			color.Red("✗ Error detecting current version: %s", err.Error())
+			os.Exit(1)
+		}
+		if *currentVersion == internal.NoGoVersion {
+			color.Red("✗ No active Go version found. Run 'gvm use <version>' first")
+			os.Exit(1)
+		}
+
+		gvmConfig, err := internal.LoadConfig()
+		if err != nil {
+			color.Red(err.Error())
+			os.Exit(1)
+		}
+
+		color.Blue("Refreshing available versions...")
+		if err := gvmConfig.UpdateAvailableVersions(); err != nil {
+			color.Red("✗ Failed to update versions: %s", err.Error())
+			os.Exit(1)
+		}
+
+		latest, err := gvmConfig.LatestPatchRelease(*currentVersion)
+		if err != nil {
+			color.Red(err.Error())
+			os.Exit(1)
+		}
+
+		if latest == nil {
+			color.Green(fmt.Sprintf("✓ %s is already the newest patch release", *currentVersion))
+			return
+		}
+
+		color.Cyan(fmt.Sprintf("Upgrading %s → %s", *currentVersion, latest.Version))
+
+		if gvmConfig.ResolveDownloadedVersion(latest.Version) == nil {
+			path := downloadVerified(cmd, gvmConfig, latest)
+
+			if err := gvmConfig.MarkVersionAsDownloaded(latest, path); err != nil {
+				color.Red(err.Error())
+				os.Exit(1)
+			}
+		}
+
+		useCmd.Run(cmd, []string{latest.Version})
+
+		if pruneOld {
+			// reload, `use` updated the config in the meantime
+			gvmConfig, err = internal.LoadConfig()
+			if err != nil {
+				color.Red(err.Error())
+				os.Exit(1)
+			}
+
+			oldVersion := gvmConfig.ResolveDownloadedVersion(*currentVersion)
+			if oldVersion == nil || oldVersion.IsLink() {
+				return
+			}
+
+			if err := gvmConfig.RemoveDownloadedVersion(oldVersion.Version); err != nil {
+				color.Red(err.Error())
+				os.Exit(1)
+			}
+			color.Yellow(fmt.Sprintf("Removed %s", oldVersion.Version))
+		}
+	},
+}
+
+func init() {
+	upgradeCmd.Flags().Bool("prune-old", false, "Remove the previously active patch release after upgrading")
+	upgradeCmd.Flags().Bool("require-signature", false, "Fail when the new archive has no valid signature of a configured key")
+	rootCmd.AddCommand(upgradeCmd)
+}
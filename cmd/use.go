@@ -25,6 +25,7 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
@@ -51,10 +52,6 @@ Examples:
 		}
 
 		requestedVersion := args[0]
-		if !internal.ValidateGoVersion(requestedVersion) {
-			color.Red(fmt.Sprintf("Input Error: Version '%s' is not a valid golang version", requestedVersion))
-			os.Exit(1)
-		}
 
 		gvmConfig, err := internal.LoadConfig()
 		if err != nil {
@@ -62,40 +59,19 @@ Examples:
 			os.Exit(1)
 		}
 
-		if !internal.ValidateGoVersion(requestedVersion) {
-			color.Red("Input Error: Invalid golang version requested")
-			os.Exit(1)
-		}
-
-		var isAvailable bool = false
-		var requiredDownloadedVersion *internal.DownloadVersion
-
-		for _, downloadedVersion := range gvmConfig.DownloadedVersions {
-			raw_version := strings.Replace(downloadedVersion.Version, "go", "", 1)
-
-			if requestedVersion == raw_version {
-				requiredDownloadedVersion = &downloadedVersion
-				break
-			}
-		}
-
-		for _, availableVersion := range gvmConfig.AvailableVersions {
-			raw_version := strings.Replace(availableVersion.Version, "go", "", 1)
+		requiredDownloadedVersion := gvmConfig.ResolveDownloadedVersion(requestedVersion)
 
-			if requestedVersion == raw_version {
-				isAvailable = true
-				break
-			}
-		}
-
-		if !isAvailable {
-			color.Red("Input Error: Invalid version %s. Version not available for download.", requestedVersion)
-			color.Blue("Run `gvm list update` to update the version list available")
+		// linked toolchains may use arbitrary names, everything else must be a version
+		if requiredDownloadedVersion == nil && !internal.ValidateGoVersion(requestedVersion) && !internal.IsDevelVersion(requestedVersion) {
+			color.Red(fmt.Sprintf("Input Error: Version '%s' is not a valid golang version", requestedVersion))
 			os.Exit(1)
 		}
 
+		isAvailable := gvmConfig.FindAvailableVersion(requestedVersion) != nil
+
 		if !isAvailable && requiredDownloadedVersion == nil {
 			color.Red(fmt.Sprintf("Input Error: Invalid version %s was asked to be used. Version neither available nor downloaded.", requestedVersion))
+			color.Blue("Run `gvm list update` to update the version list available")
 			os.Exit(1)
 		}
 
@@ -111,13 +87,10 @@ Examples:
 			}
 
 			// get the DownloadedVersion instance from the newly updated config
-			for _, downloadedVersion := range gvmConfig.DownloadedVersions {
-				raw_version := strings.Replace(downloadedVersion.Version, "go", "", 1)
-
-				if requestedVersion == raw_version {
-					requiredDownloadedVersion = &downloadedVersion
-					break
-				}
+			requiredDownloadedVersion = gvmConfig.ResolveDownloadedVersion(requestedVersion)
+			if requiredDownloadedVersion == nil {
+				color.Red(fmt.Sprintf("Download Error: version %s was not registered after download", requestedVersion))
+				os.Exit(1)
 			}
 		} else {
 			color.Green(fmt.Sprintf("Version %s is already downloaded", requestedVersion))
@@ -129,14 +102,23 @@ Examples:
 		// delete current golang installation
 		internal.PurgeCurrentGolangInstallation()
 
-		// decompress tarball
-		if _, err := internal.ExecShellCommand(fmt.Sprintf("tar -C /usr/local -xzf %s", requiredDownloadedVersion.TarPath)); err != nil {
+		// decompress tarball or link the external installation
+		if err := requiredDownloadedVersion.InstallInto("/usr/local"); err != nil {
 			color.Red(err.Error())
 			os.Exit(1)
 		}
 
-		// set the path
-		if _, err := internal.ExecShellCommand("export PATH=$PATH:/usr/local/go/bin"); err != nil {
+		// a child process can't change the PATH of the calling shell
+		if !strings.Contains(os.Getenv("PATH"), "/usr/local/go/bin") {
+			color.Yellow("Add /usr/local/go/bin to your PATH: export PATH=$PATH:/usr/local/go/bin")
+		}
+
+		usedVersion := requiredDownloadedVersion.Version
+		if _, err := internal.UpdateConfig(func(c *internal.Config) error {
+			c.DefaultVersion = usedVersion
+			c.MarkVersionUsed(usedVersion, time.Now())
+			return nil
+		}); err != nil {
 			color.Red(err.Error())
 			os.Exit(1)
 		}
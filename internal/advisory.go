@@ -0,0 +1,288 @@
+package internal
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Official golang vulnerability database, served in OSV format.
+const GO_VULN_DB_URL = "https://vuln.go.dev"
+
+// Name of the standard library (and toolchain) in the go vulnerability database.
+const stdlibModule = "stdlib"
+
+// Vulnerability entry in OSV format, see https://ossf.github.io/osv-schema.
+// Only the fields gvm needs are decoded.
+type OSVEntry struct {
+	ID       string   `json:"id"`
+	Summary  string   `json:"summary"`
+	Aliases  []string `json:"aliases"`
+	Affected []struct {
+		Package struct {
+			Name      string `json:"name"`
+			Ecosystem string `json:"ecosystem"`
+		} `json:"package"`
+		Ranges []struct {
+			Type   string `json:"type"`
+			Events []struct {
+				Introduced string `json:"introduced,omitempty"`
+				Fixed      string `json:"fixed,omitempty"`
+			} `json:"events"`
+		} `json:"ranges"`
+	} `json:"affected"`
+}
+
+// A standard library vulnerability affecting a specific go version.
+type Advisory struct {
+	ID      string   `json:"id"`
+	Summary string   `json:"summary"`
+	Aliases []string `json:"aliases,omitempty"`
+	// first release of the affected line fixing it, empty if unfixed
+	FixedIn string `json:"fixed_in,omitempty"`
+}
+
+// Loads OSV entries from feed, which is either
+//   - a local .json file holding an array of OSV entries,
+//   - a local or remote .zip file of OSV .json files,
+//   - a remote .json url holding an array of OSV entries, or
+//   - the base url of a database following the go vulndb layout
+//     (index/modules.json and ID/<id>.json), e.g. GO_VULN_DB_URL.
+func LoadAdvisories(feed string) ([]OSVEntry, error) {
+	if feed == "" {
+		feed = GO_VULN_DB_URL
+	}
+
+	isRemote := strings.HasPrefix(feed, "http://") || strings.HasPrefix(feed, "https://")
+
+	if !isRemote {
+		data, err := os.ReadFile(feed)
+		if err != nil {
+			return nil, fmt.Errorf("Advisory Error: failed to read feed: %w", err)
+		}
+		if strings.HasSuffix(feed, ".zip") {
+			return parseOSVZip(data)
+		}
+		return parseOSVArray(data)
+	}
+
+	if strings.HasSuffix(feed, ".zip") || strings.HasSuffix(feed, ".json") {
+		data, err := fetchBytes(feed)
+		if err != nil {
+			return nil, err
+		}
+		if strings.HasSuffix(feed, ".zip") {
+			return parseOSVZip(data)
+		}
+		return parseOSVArray(data)
+	}
+
+	return fetchVulnDBStdlib(strings.TrimSuffix(feed, "/"))
+}
+
+func fetchBytes(url string) ([]byte, error) {
+	response, err := HTTPClient().Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("Advisory Error: %w", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Advisory Error: failed to fetch %s. Status: %s", url, response.Status)
+	}
+
+	return io.ReadAll(response.Body)
+}
+
+func parseOSVArray(data []byte) ([]OSVEntry, error) {
+	var entries []OSVEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("Advisory Error: failed to parse OSV entries: %w", err)
+	}
+	return entries, nil
+}
+
+func parseOSVZip(data []byte) ([]OSVEntry, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("Advisory Error: invalid zip feed: %w", err)
+	}
+
+	entries := make([]OSVEntry, 0)
+	for _, f := range zr.File {
+		if !strings.HasSuffix(f.Name, ".json") || strings.Contains(f.Name, "index/") {
+			continue
+		}
+
+		rc, err := f.Open()
+		if err != nil {
+			return nil, fmt.Errorf("Advisory Error: %w", err)
+		}
+
+		var entry OSVEntry
+		err = json.NewDecoder(rc).Decode(&entry)
+		rc.Close()
+		if err != nil || entry.ID == "" {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+// Fetches the stdlib entries of a database in the go vulndb layout.
+func fetchVulnDBStdlib(baseURL string) ([]OSVEntry, error) {
+	data, err := fetchBytes(baseURL + "/index/modules.json")
+	if err != nil {
+		return nil, err
+	}
+
+	var modules []struct {
+		Path  string `json:"path"`
+		Vulns []struct {
+			ID string `json:"id"`
+		} `json:"vulns"`
+	}
+	if err := json.Unmarshal(data, &modules); err != nil {
+		return nil, fmt.Errorf("Advisory Error: failed to parse module index: %w", err)
+	}
+
+	ids := make([]string, 0)
+	for _, module := range modules {
+		if module.Path == stdlibModule {
+			for _, vuln := range module.Vulns {
+				ids = append(ids, vuln.ID)
+			}
+		}
+	}
+
+	entries := make([]OSVEntry, len(ids))
+	errs := make([]error, len(ids))
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, 8)
+
+	for idx, id := range ids {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			data, err := fetchBytes(fmt.Sprintf("%s/ID/%s.json", baseURL, id))
+			if err != nil {
+				errs[idx] = err
+				return
+			}
+			errs[idx] = json.Unmarshal(data, &entries[idx])
+		}()
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, fmt.Errorf("Advisory Error: %w", err)
+		}
+	}
+
+	return entries, nil
+}
+
+// Returns the standard library advisories affecting goVersion
+// (e.g. "go1.22.2"), sorted by id.
+func StdlibAdvisories(entries []OSVEntry, goVersion string) []Advisory {
+	version, err := ParseGoVersion(goVersion)
+	if err != nil {
+		return nil
+	}
+
+	advisories := make([]Advisory, 0)
+	for _, entry := range entries {
+		for _, affected := range entry.Affected {
+			if affected.Package.Name != stdlibModule {
+				continue
+			}
+
+			affectedVersion, fixedIn := false, ""
+			for _, r := range affected.Ranges {
+				if r.Type != "SEMVER" {
+					continue
+				}
+
+				// events are ordered introduced, fixed, introduced, fixed, ...
+				introduced := ""
+				inRange := false
+				for _, event := range r.Events {
+					if event.Introduced != "" {
+						introduced = event.Introduced
+						inRange = introduced == "0" || compareSemverEvent(version, introduced) >= 0
+					}
+					if event.Fixed != "" {
+						if inRange && compareSemverEvent(version, event.Fixed) < 0 {
+							affectedVersion, fixedIn = true, event.Fixed
+						}
+						inRange = false
+					}
+				}
+				if inRange {
+					affectedVersion = true
+				}
+			}
+
+			if affectedVersion {
+				advisory := Advisory{ID: entry.ID, Summary: entry.Summary, Aliases: entry.Aliases}
+				if fixed, ok := parseSemver(strings.TrimSuffix(fixedIn, "-0")); ok {
+					advisory.FixedIn = fixed.String()
+				}
+				advisories = append(advisories, advisory)
+				break
+			}
+		}
+	}
+
+	sort.Slice(advisories, func(i, j int) bool {
+		return advisories[i].ID < advisories[j].ID
+	})
+
+	return advisories
+}
+
+// Returns the lowest version fixing every advisory, empty if any of
+// them is unfixed in the affected release line.
+func MinimumFixedVersion(advisories []Advisory) string {
+	minimum := ""
+	var minimumVersion GoVersion
+
+	for _, advisory := range advisories {
+		if advisory.FixedIn == "" {
+			return ""
+		}
+
+		fixed, err := ParseGoVersion(advisory.FixedIn)
+		if err != nil {
+			continue
+		}
+		if minimum == "" || minimumVersion.Less(fixed) {
+			minimum, minimumVersion = advisory.FixedIn, fixed
+		}
+	}
+
+	return minimum
+}
+
+// Compares a version against a semver range event of an OSV entry.
+// Unparsable events compare as equal.
+func compareSemverEvent(version GoVersion, event string) int {
+	eventVersion, ok := parseSemver(event)
+	if !ok {
+		return 0
+	}
+	return version.Compare(eventVersion)
+}
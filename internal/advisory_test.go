@@ -0,0 +1,76 @@
+package internal
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestStdlibAdvisories(t *testing.T) {
+	entries, err := LoadAdvisories(filepath.Join("testdata", "osv.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		version string
+		wantIDs []string
+		// fix version of each advisory in wantIDs
+		wantFixed []string
+		wantMin   string
+	}{
+		{version: "go1.22.3", wantIDs: []string{"GO-2025-0001"}, wantFixed: []string{"go1.24.8"}, wantMin: "go1.24.8"},
+		{version: "go1.23.4", wantIDs: []string{"GO-2025-0001", "GO-2025-0005"}, wantFixed: []string{"go1.24.8", "go1.23.9"}, wantMin: "go1.24.8"},
+		{version: "go1.24.4", wantIDs: []string{"GO-2025-0001", "GO-2025-0005"}, wantFixed: []string{"go1.24.8", "go1.24.5"}, wantMin: "go1.24.8"},
+		{version: "go1.24.7", wantIDs: []string{"GO-2025-0001"}, wantFixed: []string{"go1.24.8"}, wantMin: "go1.24.8"},
+		{version: "go1.24.8", wantIDs: []string{}},
+		{version: "go1.25rc1", wantIDs: []string{"GO-2025-0001", "GO-2025-0002"}, wantFixed: []string{"go1.25.2", "go1.25.4"}, wantMin: "go1.25.4"},
+		{version: "go1.25.0", wantIDs: []string{"GO-2025-0001", "GO-2025-0002"}, wantFixed: []string{"go1.25.2", "go1.25.4"}, wantMin: "go1.25.4"},
+		{version: "go1.25.2", wantIDs: []string{"GO-2025-0002"}, wantFixed: []string{"go1.25.4"}, wantMin: "go1.25.4"},
+		{version: "go1.25.4", wantIDs: []string{}},
+		{version: "go1.26rc1", wantIDs: []string{"GO-2025-0003"}, wantFixed: []string{"go1.26rc2"}, wantMin: "go1.26rc2"},
+		{version: "go1.26rc2", wantIDs: []string{}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.version, func(t *testing.T) {
+			advisories := StdlibAdvisories(entries, test.version)
+
+			ids := make([]string, 0, len(advisories))
+			fixed := make([]string, 0, len(advisories))
+			for _, advisory := range advisories {
+				ids = append(ids, advisory.ID)
+				fixed = append(fixed, advisory.FixedIn)
+			}
+			if !slices.Equal(ids, test.wantIDs) {
+				t.Fatalf("StdlibAdvisories(%s) = %v, want %v", test.version, ids, test.wantIDs)
+			}
+			if len(test.wantIDs) > 0 && !slices.Equal(fixed, test.wantFixed) {
+				t.Errorf("StdlibAdvisories(%s) fixed in %v, want %v", test.version, fixed, test.wantFixed)
+			}
+
+			if got := MinimumFixedVersion(advisories); got != test.wantMin {
+				t.Errorf("MinimumFixedVersion(%s) = %q, want %q", test.version, got, test.wantMin)
+			}
+		})
+	}
+}
+
+func TestMinimumFixedVersion(t *testing.T) {
+	tests := []struct {
+		name       string
+		advisories []Advisory
+		want       string
+	}{
+		{name: "none", want: ""},
+		{name: "highest fix wins", advisories: []Advisory{{FixedIn: "go1.25.4"}, {FixedIn: "go1.25.10"}, {FixedIn: "go1.25.2"}}, want: "go1.25.10"},
+		{name: "unfixed advisory", advisories: []Advisory{{FixedIn: "go1.25.4"}, {FixedIn: ""}}, want: ""},
+		{name: "release after prerelease", advisories: []Advisory{{FixedIn: "go1.26rc2"}, {FixedIn: "go1.26.0"}}, want: "go1.26.0"},
+	}
+
+	for _, test := range tests {
+		if got := MinimumFixedVersion(test.advisories); got != test.want {
+			t.Errorf("%s: MinimumFixedVersion() = %q, want %q", test.name, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package internal
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Upstream golang source repository used when a ref instead of a local
+// checkout is given to `gvm install --source`.
+const GO_SOURCE_REPOSITORY_URL = "https://go.googlesource.com/go"
+
+// Prefix of versions built from source, e.g. "devel-1a2b3c4d5e6f".
+const DevelVersionPrefix = "devel-"
+
+// Reports whether version names a toolchain built from source.
+func IsDevelVersion(version string) bool {
+	return strings.HasPrefix(version, DevelVersionPrefix)
+}
+
+// Builds golang from source and packs the result into the download
+// cache as "devel-<sha>.tar.gz".
+// source is either a local git checkout of the go repository, which is
+// built without any network access, or a branch, tag or commit fetched
+// from GO_SOURCE_REPOSITORY_URL.
+func BuildGoFromSource(source string, bootstrapRoot string) (*DownloadVersion, error) {
+	if _, err := exec.LookPath("git"); err != nil {
+		return nil, fmt.Errorf("Build Error: git is required to build go from source")
+	}
+
+	workDir, err := os.MkdirTemp("", "gvm-build-")
+	if err != nil {
+		return nil, fmt.Errorf("Build Error: failed to create work directory: %w", err)
+	}
+	defer os.RemoveAll(workDir)
+
+	goroot := filepath.Join(workDir, "go")
+	version, err := checkoutGoSource(GO_SOURCE_REPOSITORY_URL, source, goroot)
+	if err != nil {
+		return nil, err
+	}
+
+	env := append(os.Environ(), fmt.Sprintf("GOROOT_BOOTSTRAP=%s", bootstrapRoot))
+	if err := runCommand(filepath.Join(goroot, "src"), env, "bash", "make.bash"); err != nil {
+		return nil, fmt.Errorf("Build Error: make.bash failed: %w", err)
+	}
+
+	if err := os.RemoveAll(filepath.Join(goroot, ".git")); err != nil {
+		return nil, fmt.Errorf("Build Error: %w", err)
+	}
+
+	fileName := fmt.Sprintf("%s.tar.gz", version)
+	tarPath := filepath.Join(workDir, fileName)
+	if err := CreateTarGz(goroot, "go", tarPath); err != nil {
+		return nil, err
+	}
+
+	entry, err := AddFileToCache(tarPath, fileName, "", true)
+	if err != nil {
+		return nil, err
+	}
+
+	viewPath, err := LinkCachedArchive(entry, fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DownloadVersion{
+		Version: version,
+		TarPath: viewPath,
+		SHA256:  entry.SHA256,
+	}, nil
+}
+
+// Checks the go source out into goroot, either by cloning the local
+// checkout at source or by fetching the branch, tag or commit source from
+// repository. Returns the devel version named after the checked out commit.
+func checkoutGoSource(repository string, source string, goroot string) (string, error) {
+	if info, err := os.Stat(source); err == nil && info.IsDir() {
+		if err := runCommand("", nil, "git", "clone", "--quiet", "--local", source, goroot); err != nil {
+			return "", fmt.Errorf("Build Error: failed to clone local checkout %s: %w", source, err)
+		}
+	} else {
+		if err := fetchSourceRef(repository, source, goroot); err != nil {
+			return "", err
+		}
+	}
+
+	sha, err := exec.Command("git", "-C", goroot, "rev-parse", "--short=12", "HEAD").Output()
+	if err != nil {
+		return "", fmt.Errorf("Build Error: failed to resolve source commit: %w", err)
+	}
+
+	return DevelVersionPrefix + strings.TrimSpace(string(sha)), nil
+}
+
+func fetchSourceRef(repository string, ref string, goroot string) error {
+	steps := [][]string{
+		{"git", "init", "--quiet", goroot},
+		{"git", "-C", goroot, "fetch", "--quiet", "--depth", "1", repository, ref},
+		{"git", "-C", goroot, "checkout", "--quiet", "FETCH_HEAD"},
+	}
+
+	for _, step := range steps {
+		if err := runCommand("", nil, step[0], step[1:]...); err != nil {
+			return fmt.Errorf("Build Error: failed to fetch go source at %s: %w", ref, err)
+		}
+	}
+
+	return nil
+}
+
+// Runs a command with its output attached to the terminal.
+func runCommand(dir string, env []string, name string, args ...string) error {
+	command := exec.Command(name, args...)
+	command.Dir = dir
+	command.Env = env
+	command.Stdout = os.Stdout
+	command.Stderr = os.Stderr
+
+	return command.Run()
+}
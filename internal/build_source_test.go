@@ -0,0 +1,95 @@
+package internal
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Runs git in dir and returns its trimmed output.
+func runTestGit(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+
+	out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, out)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+func TestCheckoutGoSource(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	for _, name := range []string{"GIT_AUTHOR_NAME", "GIT_COMMITTER_NAME"} {
+		t.Setenv(name, "gvm")
+	}
+	for _, name := range []string{"GIT_AUTHOR_EMAIL", "GIT_COMMITTER_EMAIL"} {
+		t.Setenv(name, "gvm@example.com")
+	}
+
+	// a go repository with a tagged first commit, a release branch and a
+	// newer master
+	repository := t.TempDir()
+	runTestGit(t, repository, "init", "--quiet", "--initial-branch", "master")
+	runTestGit(t, repository, "config", "uploadpack.allowAnySHA1InWant", "true")
+	commit := func(version string) string {
+		if err := os.WriteFile(filepath.Join(repository, "VERSION"), []byte(version+"\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		runTestGit(t, repository, "add", "VERSION")
+		runTestGit(t, repository, "commit", "--quiet", "-m", version)
+		return runTestGit(t, repository, "rev-parse", "HEAD")
+	}
+	tagged := commit("go1.25.0")
+	runTestGit(t, repository, "tag", "go1.25.0")
+	runTestGit(t, repository, "checkout", "--quiet", "-b", "release-branch.go1.25")
+	branch := commit("go1.25.1")
+	runTestGit(t, repository, "checkout", "--quiet", "master")
+	master := commit("devel")
+
+	notARepository := t.TempDir()
+
+	tests := []struct {
+		name    string
+		source  string
+		want    string
+		wantErr string
+	}{
+		{name: "local checkout at HEAD", source: repository, want: master},
+		{name: "branch", source: "release-branch.go1.25", want: branch},
+		{name: "tag", source: "go1.25.0", want: tagged},
+		{name: "commit", source: branch, want: branch},
+		{name: "unknown ref", source: "release-branch.go1.99", wantErr: "failed to fetch go source at release-branch.go1.99"},
+		{name: "local directory without git", source: notARepository, wantErr: "failed to clone local checkout"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			goroot := filepath.Join(t.TempDir(), "go")
+
+			version, err := checkoutGoSource("file://"+repository, test.source, goroot)
+			if test.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+					t.Fatalf("checkoutGoSource() = %s, %v, want error containing %q", version, err, test.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("checkoutGoSource() = %v", err)
+			}
+
+			if want := DevelVersionPrefix + test.want[:12]; version != want {
+				t.Errorf("checkoutGoSource() = %s, want %s", version, want)
+			}
+			if head := runTestGit(t, goroot, "rev-parse", "HEAD"); head != test.want {
+				t.Errorf("checked out %s, want %s", head, test.want)
+			}
+			if !IsDevelVersion(version) {
+				t.Errorf("IsDevelVersion(%s) = false", version)
+			}
+		})
+	}
+}
@@ -0,0 +1,388 @@
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// Directory inside the download directory holding the shared,
+	// content addressed archive store.
+	CacheDirName = "sha256"
+	// Per user directory holding hardlinks (or copies) of the cached
+	// archives the user downloaded.
+	UserVersionsDirName = "go-versions"
+	cacheMetaSuffix     = ".json"
+)
+
+// An archive in the shared download cache, stored as
+// <CacheDir>/<sha256> with its metadata in <sha256>.json.
+type CacheEntry struct {
+	SHA256 string `json:"-"`
+	Path   string `json:"-"`
+	Size   int64  `json:"-"`
+	// last time the archive was downloaded or linked by any user
+	LastUsed time.Time `json:"-"`
+	// file names the archive was stored under, e.g. "go1.25.5.tar.gz"
+	Names []string `json:"names"`
+	// urls the archive was downloaded from
+	URLs    []string `json:"urls,omitempty"`
+	AddedAt int64    `json:"added_at"`
+}
+
+// Returns the shared content addressed store, creating it if needed.
+func CacheDir() (string, error) {
+	downloadDirPath, err := GoDownloadDir()
+	if err != nil {
+		return "", err
+	}
+
+	cacheDir := filepath.Join(*downloadDirPath, CacheDirName)
+	if err := os.MkdirAll(cacheDir, 0755); err != nil {
+		return "", fmt.Errorf("Cache Error: failed to create %s: %w", cacheDir, err)
+	}
+
+	return cacheDir, nil
+}
+
+// Returns the per user view of downloaded archives, creating it if
+// needed. Honors XDG_DATA_HOME and defaults to ~/.local/share/gvm.
+func UserVersionsDir() (string, error) {
+	dataHome := os.Getenv("XDG_DATA_HOME")
+	if dataHome == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to get home directory: %w", err)
+		}
+		dataHome = filepath.Join(home, ".local", "share")
+	}
+
+	dir := filepath.Join(dataHome, AppName, UserVersionsDirName)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("Cache Error: failed to create %s: %w", dir, err)
+	}
+
+	return dir, nil
+}
+
+// Adds the file at path to the cache under its sha256 and records name
+// and url (optional) in the entry metadata. With move the file is
+// renamed into the cache, otherwise it is copied.
+func AddFileToCache(path string, name string, url string, move bool) (*CacheEntry, error) {
+	cacheDir, err := CacheDir()
+	if err != nil {
+		return nil, err
+	}
+
+	sha, err := FileSHA256(path)
+	if err != nil {
+		return nil, fmt.Errorf("Cache Error: failed to hash %s: %w", path, err)
+	}
+
+	objectPath := filepath.Join(cacheDir, sha)
+	if _, err := os.Stat(objectPath); err == nil {
+		// identical content already cached
+		if move {
+			os.Remove(path)
+		}
+	} else if move {
+		if err := os.Rename(path, objectPath); err != nil {
+			if err := copyFile(path, objectPath); err != nil {
+				return nil, fmt.Errorf("Cache Error: %w", err)
+			}
+			os.Remove(path)
+		}
+	} else {
+		if err := copyFile(path, objectPath); err != nil {
+			return nil, fmt.Errorf("Cache Error: %w", err)
+		}
+	}
+	os.Chmod(objectPath, 0644)
+
+	entry, err := readCacheEntry(cacheDir, sha)
+	if err != nil {
+		entry = &CacheEntry{SHA256: sha, Path: objectPath, AddedAt: time.Now().UnixMilli()}
+	}
+	entry.Names = appendUnique(entry.Names, name)
+	if url != "" {
+		entry.URLs = appendUnique(entry.URLs, url)
+	}
+
+	if err := writeCacheEntry(cacheDir, entry); err != nil {
+		return nil, err
+	}
+
+	return readCacheEntry(cacheDir, sha)
+}
+
+// Returns the cache entry for the sha256 or nil if it isn't cached.
+func LookupCacheBySHA256(sha string) (*CacheEntry, error) {
+	cacheDir, err := CacheDir()
+	if err != nil {
+		return nil, err
+	}
+
+	sha = strings.ToLower(sha)
+	if !isCacheKey(sha) {
+		return nil, nil
+	}
+
+	entry, err := readCacheEntry(cacheDir, sha)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	return entry, err
+}
+
+// Returns the cache entry previously downloaded from url or nil.
+func LookupCacheByURL(url string) (*CacheEntry, error) {
+	entries, err := ListCache()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range entries {
+		for _, entryURL := range entry.URLs {
+			if entryURL == url {
+				return &entry, nil
+			}
+		}
+	}
+
+	return nil, nil
+}
+
+// Makes a cached archive available as fileName in the per user view,
+// by hardlink when possible and by copy otherwise, and marks the entry
+// as used. Returns the path in the view.
+func LinkCachedArchive(entry *CacheEntry, fileName string) (string, error) {
+	viewDir, err := UserVersionsDir()
+	if err != nil {
+		return "", err
+	}
+
+	viewPath := filepath.Join(viewDir, fileName)
+	if info, err := os.Stat(viewPath); err == nil {
+		if objectInfo, err := os.Stat(entry.Path); err == nil && os.SameFile(info, objectInfo) {
+			touchCacheEntry(entry)
+			return viewPath, nil
+		}
+		if err := os.Remove(viewPath); err != nil {
+			return "", fmt.Errorf("Cache Error: failed to replace %s: %w", viewPath, err)
+		}
+	}
+
+	if err := os.Link(entry.Path, viewPath); err != nil {
+		// different filesystem or hardlinks not permitted
+		if err := copyFile(entry.Path, viewPath); err != nil {
+			return "", fmt.Errorf("Cache Error: failed to link %s into %s: %w", entry.Path, viewDir, err)
+		}
+	}
+
+	touchCacheEntry(entry)
+	return viewPath, nil
+}
+
+// Lists every archive in the cache, least recently used first.
+func ListCache() ([]CacheEntry, error) {
+	cacheDir, err := CacheDir()
+	if err != nil {
+		return nil, err
+	}
+
+	return listCacheDir(cacheDir)
+}
+
+func listCacheDir(cacheDir string) ([]CacheEntry, error) {
+	dirEntries, err := os.ReadDir(cacheDir)
+	if err != nil {
+		return nil, fmt.Errorf("Cache Error: %w", err)
+	}
+
+	entries := make([]CacheEntry, 0)
+	for _, dirEntry := range dirEntries {
+		name := dirEntry.Name()
+		// archives are stored under their digest, anything else is a stray
+		if dirEntry.IsDir() || !isCacheKey(name) {
+			continue
+		}
+
+		entry, err := readCacheEntry(cacheDir, name)
+		if err != nil {
+			continue
+		}
+		entries = append(entries, *entry)
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].LastUsed.Before(entries[j].LastUsed)
+	})
+
+	return entries, nil
+}
+
+// Reports whether name is a cache key: a lower case hex sha256 digest.
+func isCacheKey(name string) bool {
+	return isSHA256Hex(name) && name == strings.ToLower(name)
+}
+
+// Abbreviates a checksum for display like git does for commits.
+func ShortChecksum(checksum string) string {
+	if checksum == "" {
+		return "unknown"
+	}
+	return checksum[:min(12, len(checksum))]
+}
+
+// Rehashes a cached archive and reports whether it still matches the
+// sha256 it is stored under.
+func VerifyCacheEntry(entry *CacheEntry) error {
+	actual, err := FileSHA256(entry.Path)
+	if err != nil {
+		return fmt.Errorf("Cache Error: failed to hash %s: %w", entry.Path, err)
+	}
+
+	if actual != entry.SHA256 {
+		return fmt.Errorf("Cache Error: %s is corrupt, content hashes to %s", entry.Path, actual)
+	}
+
+	return nil
+}
+
+// Removes an archive and its metadata from the cache. Hardlinks in the
+// per user views keep their content until they are removed as well.
+func RemoveCacheEntry(entry *CacheEntry) error {
+	if err := os.Remove(entry.Path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("Cache Error: failed to remove %s: %w", entry.Path, err)
+	}
+	os.Remove(entry.Path + cacheMetaSuffix)
+
+	return nil
+}
+
+// Selects the entries to evict: every entry unused for longer than
+// olderThan (zero disables the age policy) and then, least recently
+// used first, as many as needed to get the cache below maxSize (zero
+// disables the size policy).
+func SelectCacheEvictions(entries []CacheEntry, olderThan time.Duration, maxSize int64, now time.Time) []CacheEntry {
+	sorted := make([]CacheEntry, len(entries))
+	copy(sorted, entries)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].LastUsed.Before(sorted[j].LastUsed)
+	})
+
+	var totalSize int64
+	for _, entry := range sorted {
+		totalSize += entry.Size
+	}
+
+	evictions := make([]CacheEntry, 0)
+	for _, entry := range sorted {
+		tooOld := olderThan > 0 && now.Sub(entry.LastUsed) > olderThan
+		tooLarge := maxSize > 0 && totalSize > maxSize
+
+		if tooOld || tooLarge {
+			evictions = append(evictions, entry)
+			totalSize -= entry.Size
+		}
+	}
+
+	return evictions
+}
+
+func readCacheEntry(cacheDir string, sha string) (*CacheEntry, error) {
+	objectPath := filepath.Join(cacheDir, sha)
+
+	info, err := os.Stat(objectPath)
+	if err != nil {
+		return nil, err
+	}
+
+	entry := &CacheEntry{}
+	if data, err := os.ReadFile(objectPath + cacheMetaSuffix); err == nil {
+		// a broken sidecar only loses names, the archive stays usable
+		json.Unmarshal(data, entry)
+	}
+
+	entry.SHA256 = sha
+	entry.Path = objectPath
+	entry.Size = info.Size()
+	entry.LastUsed = info.ModTime()
+
+	return entry, nil
+}
+
+func writeCacheEntry(cacheDir string, entry *CacheEntry) error {
+	data, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		return fmt.Errorf("Cache Error: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(cacheDir, ".meta-")
+	if err != nil {
+		return fmt.Errorf("Cache Error: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("Cache Error: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("Cache Error: %w", err)
+	}
+	os.Chmod(tmp.Name(), 0644)
+
+	return os.Rename(tmp.Name(), filepath.Join(cacheDir, entry.SHA256+cacheMetaSuffix))
+}
+
+// The modification time of a cached archive doubles as its last use.
+func touchCacheEntry(entry *CacheEntry) {
+	now := time.Now()
+	if err := os.Chtimes(entry.Path, now, now); err == nil {
+		entry.LastUsed = now
+	}
+}
+
+// Downloads into the cache through a temporary file, see RemoteVersion.Download.
+// The file keeps fileName as suffix, so it can be inspected by its type
+// and is recognizable in messages before it's added to the cache.
+func downloadToCache(body io.Reader, fileName string, progress io.Writer) (string, error) {
+	cacheDir, err := CacheDir()
+	if err != nil {
+		return "", err
+	}
+
+	tmp, err := os.CreateTemp(cacheDir, ".download-*-"+fileName)
+	if err != nil {
+		return "", fmt.Errorf("Cache Error: %w", err)
+	}
+
+	if _, err := io.Copy(io.MultiWriter(tmp, progress), body); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return "", err
+	}
+
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return "", err
+	}
+
+	return tmp.Name(), nil
+}
+
+func appendUnique(values []string, value string) []string {
+	for _, v := range values {
+		if v == value {
+			return values
+		}
+	}
+	return append(values, value)
+}
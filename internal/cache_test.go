@@ -0,0 +1,53 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestListCacheDirSkipsStrayFiles(t *testing.T) {
+	cacheDir := t.TempDir()
+	sha := strings.Repeat("ab", 32)
+
+	files := []string{
+		sha,
+		sha + cacheMetaSuffix,
+		strings.ToUpper(strings.Repeat("cd", 32)),
+		strings.Repeat("ef", 31),
+		".meta-123",
+		"README",
+		"notes.json",
+	}
+	for _, name := range files {
+		if err := os.WriteFile(filepath.Join(cacheDir, name), []byte("{}"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Mkdir(filepath.Join(cacheDir, strings.Repeat("01", 32)), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := listCacheDir(cacheDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].SHA256 != sha {
+		t.Fatalf("listCacheDir() = %+v, want only %s", entries, sha)
+	}
+}
+
+func TestShortChecksum(t *testing.T) {
+	tests := map[string]string{
+		"":                                       "unknown",
+		"abc":                                    "abc",
+		strings.Repeat("0123456789", 6) + "abcd": "012345678901",
+	}
+
+	for checksum, want := range tests {
+		if got := ShortChecksum(checksum); got != want {
+			t.Errorf("ShortChecksum(%q) = %q, want %q", checksum, got, want)
+		}
+	}
+}
@@ -0,0 +1,356 @@
+package internal
+
+import (
+	"bufio"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Names of the checks run by CheckToolchainConsistency.
+const (
+	CheckActive         = "active"
+	CheckGoVersionFile  = "go-version"
+	CheckGoDirective    = "go.mod go"
+	CheckGoModToolchain = "go.mod toolchain"
+	CheckPinConflict    = "go.mod vs go-version"
+	CheckLockfile       = "lockfile"
+	CheckLockedVersion  = "locked version"
+	CheckLockedSHA256   = "locked sha256"
+)
+
+// Outcome of a single toolchain consistency check. File and Line point
+// at the requirement, e.g. for CI annotations.
+type ToolchainCheck struct {
+	Name     string `json:"name"`
+	OK       bool   `json:"ok"`
+	File     string `json:"file,omitempty"`
+	Line     int    `json:"line,omitempty"`
+	Expected string `json:"expected"`
+	Actual   string `json:"actual"`
+	Message  string `json:"message"`
+}
+
+// Result of checking the active toolchain against the requirements of
+// a directory.
+type ToolchainReport struct {
+	Dir    string           `json:"dir"`
+	Active *ActiveVersion   `json:"active"`
+	Checks []ToolchainCheck `json:"checks"`
+	OK     bool             `json:"ok"`
+}
+
+// Checks that the active toolchain satisfies the nearest .go-version,
+// the go and toolchain directives of the nearest go.mod and gvm.lock,
+// and that these requirements agree with each other.
+func CheckToolchainConsistency(dir string, active *ActiveVersion) *ToolchainReport {
+	report := &ToolchainReport{Dir: dir, Active: active, Checks: make([]ToolchainCheck, 0)}
+
+	var activeVersion *GoVersion
+	switch {
+	case active == nil:
+		report.add(ToolchainCheck{
+			Name:     CheckActive,
+			Expected: "an installed go toolchain",
+			Actual:   NoGoVersion,
+			Message:  "no go toolchain found in PATH",
+		})
+	case !active.Installed:
+		report.add(ToolchainCheck{
+			Name:     CheckActive,
+			File:     fileSource(active.Source),
+			Expected: active.Version,
+			Actual:   "not installed",
+			Message:  fmt.Sprintf("Go %s requested by %s is not installed", active.Version, active.Source),
+		})
+	default:
+		if v, err := ParseGoVersion(active.Version); err == nil {
+			activeVersion = &v
+		}
+	}
+
+	pinPath, hasPin := findUp(dir, GoVersionFile)
+	pin := ""
+	if hasPin {
+		pin = readGoVersionFile(pinPath)
+		report.checkPin(pinPath, pin, active, activeVersion)
+	}
+
+	goModPath, hasGoMod := findUp(dir, "go.mod")
+	if hasGoMod {
+		goDirective, toolchain := readGoModDirectives(goModPath)
+		if goDirective != "" {
+			report.checkMinimum(CheckGoDirective, goModPath, "go", goDirective, active, activeVersion)
+		}
+		if toolchain != "" {
+			report.checkMinimum(CheckGoModToolchain, goModPath, "toolchain", toolchain, active, activeVersion)
+		}
+		if goDirective != "" && pin != "" {
+			report.checkPinConflict(goModPath, goDirective, pinPath, pin)
+		}
+	}
+
+	if lockPath, ok := FindLockfile(dir); ok {
+		report.checkLockfile(dir, lockPath, active, activeVersion)
+	}
+
+	report.OK = true
+	for _, check := range report.Checks {
+		report.OK = report.OK && check.OK
+	}
+
+	return report
+}
+
+func (r *ToolchainReport) add(check ToolchainCheck) {
+	r.Checks = append(r.Checks, check)
+}
+
+// Checks the active version against the .go-version pin, resolved like
+// the shims do: "1.25" accepts any go1.25.x release, "1.25.5" only itself.
+func (r *ToolchainReport) checkPin(path string, pin string, active *ActiveVersion, activeVersion *GoVersion) {
+	check := ToolchainCheck{
+		Name: CheckGoVersionFile,
+		File: path,
+		Line: lineNumber(path, func(line string) bool { return line != "" && !strings.HasPrefix(line, "#") }),
+	}
+
+	pinned, err := ParseGoVersion(pin)
+	if err != nil {
+		check.Expected, check.Actual = "a golang version", pin
+		check.Message = fmt.Sprintf("%s pins '%s' which is not a valid golang version", filepath.Base(path), pin)
+		r.add(check)
+		return
+	}
+
+	check.Expected = pinned.String()
+	if !pinned.HasPatch() && !pinned.IsPrerelease() {
+		check.Expected = pinned.MinorLine() + ".x"
+	}
+
+	if activeVersion == nil {
+		if active != nil && active.Installed {
+			check.Actual = active.Version
+			check.Message = fmt.Sprintf("active go %s is not a release version", active.Version)
+			r.add(check)
+		}
+		return
+	}
+
+	check.Actual = activeVersion.String()
+	_, check.OK = SelectVersion(&VersionRequest{Version: pin, Source: path}, []string{activeVersion.String()})
+	if check.OK {
+		check.Message = fmt.Sprintf("%s satisfies %s", check.Actual, filepath.Base(path))
+	} else {
+		check.Message = fmt.Sprintf("%s pins %s but %s is active", filepath.Base(path), pin, check.Actual)
+	}
+	r.add(check)
+}
+
+// Checks the active version is at least the version of a go.mod
+// directive. Both the go and the toolchain directive name minimums.
+func (r *ToolchainReport) checkMinimum(name string, path string, directive string, required string, active *ActiveVersion, activeVersion *GoVersion) {
+	check := ToolchainCheck{
+		Name: name,
+		File: path,
+		Line: lineNumber(path, func(line string) bool {
+			fields := strings.Fields(line)
+			return len(fields) == 2 && fields[0] == directive
+		}),
+	}
+
+	minimum, err := ParseGoVersion(required)
+	if err != nil {
+		check.Expected, check.Actual = "a golang version", required
+		check.Message = fmt.Sprintf("go.mod %s directive '%s' is not a valid golang version", directive, required)
+		r.add(check)
+		return
+	}
+
+	check.Expected = ">= " + minimum.String()
+	if activeVersion == nil {
+		if active != nil && active.Installed {
+			check.Actual = active.Version
+			check.Message = fmt.Sprintf("active go %s is not a release version", active.Version)
+			r.add(check)
+		}
+		return
+	}
+
+	check.Actual = activeVersion.String()
+	check.OK = !activeVersion.Less(minimum)
+	if check.OK {
+		check.Message = fmt.Sprintf("%s satisfies %s %s", check.Actual, directive, minimum.Number())
+	} else {
+		check.Message = fmt.Sprintf("go.mod %s directive requires %s or newer but %s is active", directive, minimum.String(), check.Actual)
+	}
+	r.add(check)
+}
+
+// Checks that the .go-version pin allows a release at least as new as
+// the go directive of go.mod, which would otherwise never build.
+func (r *ToolchainReport) checkPinConflict(goModPath string, goDirective string, pinPath string, pin string) {
+	required, errRequired := ParseGoVersion(goDirective)
+	pinned, errPinned := ParseGoVersion(pin)
+	if errRequired != nil || errPinned != nil {
+		return
+	}
+
+	// newest release the pin allows, "1.25" allows every go1.25.x
+	newest := pinned
+	if !pinned.HasPatch() && !pinned.IsPrerelease() {
+		newest.Patch = math.MaxInt
+	}
+
+	check := ToolchainCheck{
+		Name:     CheckPinConflict,
+		OK:       !newest.Less(required),
+		File:     goModPath,
+		Expected: ">= " + required.String(),
+		Actual:   pin,
+		Line: lineNumber(goModPath, func(line string) bool {
+			fields := strings.Fields(line)
+			return len(fields) == 2 && fields[0] == "go"
+		}),
+	}
+	if check.OK {
+		check.Message = fmt.Sprintf("%s pin %s satisfies go %s", filepath.Base(pinPath), pin, required.Number())
+	} else {
+		check.Message = fmt.Sprintf("go.mod requires go %s but %s pins %s", required.Number(), filepath.Base(pinPath), pin)
+	}
+	r.add(check)
+}
+
+// Checks that gvm.lock is up to date and that the active toolchain is
+// the one locked for the project pin, with the locked checksum.
+func (r *ToolchainReport) checkLockfile(dir string, lockPath string, active *ActiveVersion, activeVersion *GoVersion) {
+	lockfile, err := LoadLockfile(lockPath)
+	if err != nil {
+		r.add(ToolchainCheck{Name: CheckLockfile, File: lockPath, Expected: "a valid lockfile", Actual: "invalid", Message: err.Error()})
+		return
+	}
+
+	requests, _, err := ProjectLockRequests(dir, nil)
+	if err != nil {
+		r.add(ToolchainCheck{Name: CheckLockfile, File: lockPath, Expected: "up to date", Actual: "unknown", Message: err.Error()})
+		return
+	}
+
+	check := ToolchainCheck{Name: CheckLockfile, File: lockPath, Expected: "up to date", Actual: "up to date"}
+	if problems := lockfile.Outdated(requests); problems != nil {
+		check.Actual = "out of date"
+		check.Message = fmt.Sprintf("%s is out of date, run 'gvm lock': %s", LockFileName, strings.Join(problems, "; "))
+	} else {
+		check.OK = true
+		check.Message = fmt.Sprintf("%s matches %s", LockFileName, describeLockSources(requests))
+	}
+	r.add(check)
+
+	pin := ResolveProjectVersionRequest(dir)
+	if pin == nil || activeVersion == nil {
+		return
+	}
+
+	locked := lockfile.findToolchain(relativeLockPath(filepath.Dir(lockPath), pin.Source))
+	if locked == nil {
+		return
+	}
+
+	lockedLine := lineNumber(lockPath, func(line string) bool {
+		return line == fmt.Sprintf("[toolchains.%s]", tomlKey(locked.Name))
+	})
+
+	versionCheck := ToolchainCheck{
+		Name:     CheckLockedVersion,
+		OK:       SameGoVersion(locked.Version, activeVersion.String()),
+		File:     lockPath,
+		Line:     lockedLine,
+		Expected: locked.Version,
+		Actual:   activeVersion.String(),
+	}
+	if versionCheck.OK {
+		versionCheck.Message = fmt.Sprintf("%s is the version locked for %s", versionCheck.Actual, locked.Name)
+	} else {
+		versionCheck.Message = fmt.Sprintf("%s locks %s for %s but %s is active", LockFileName, locked.Version, locked.Name, versionCheck.Actual)
+	}
+	r.add(versionCheck)
+
+	// the checksum is only known for toolchains installed by gvm
+	artifact, ok := locked.Artifacts[CurrentPlatform()]
+	if !versionCheck.OK || !ok || !ConfigExists() {
+		return
+	}
+	gvmConfig, err := LoadConfig()
+	if err != nil {
+		return
+	}
+	downloaded := gvmConfig.ResolveDownloadedVersion(active.Version)
+	if downloaded == nil || downloaded.IsLink() || downloaded.SHA256 == "" {
+		return
+	}
+
+	shaCheck := ToolchainCheck{
+		Name:     CheckLockedSHA256,
+		OK:       strings.EqualFold(downloaded.SHA256, artifact.SHA256),
+		File:     lockPath,
+		Line:     lockedLine,
+		Expected: artifact.SHA256,
+		Actual:   downloaded.SHA256,
+	}
+	if shaCheck.OK {
+		shaCheck.Message = fmt.Sprintf("installed %s matches the locked sha256", downloaded.Version)
+	} else {
+		shaCheck.Message = fmt.Sprintf("installed %s has sha256 %s, %s locks %s", downloaded.Version, ShortChecksum(downloaded.SHA256), LockFileName, ShortChecksum(artifact.SHA256))
+	}
+	r.add(shaCheck)
+}
+
+func (l *Lockfile) findToolchain(name string) *LockedToolchain {
+	for _, toolchain := range l.Toolchains {
+		if toolchain.Name == name {
+			return &toolchain
+		}
+	}
+	return nil
+}
+
+func describeLockSources(requests []LockRequest) string {
+	sources := make([]string, 0, len(requests))
+	for _, request := range requests {
+		if !slices.Contains(sources, request.Source) {
+			sources = append(sources, request.Source)
+		}
+	}
+	return strings.Join(sources, ", ")
+}
+
+// Returns the version source when it's a file, empty for the environment
+// variable, the default version and PATH.
+func fileSource(source string) string {
+	if source == VersionEnvVar || source == DefaultVersionSource || source == PathVersionSource {
+		return ""
+	}
+	return source
+}
+
+// Returns the 1-based number of the first line matching, 0 when none
+// does. Lines are trimmed and stripped of // comments.
+func lineNumber(path string, match func(line string) bool) int {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for number := 1; scanner.Scan(); number++ {
+		line, _, _ := strings.Cut(scanner.Text(), "//")
+		if match(strings.TrimSpace(line)) {
+			return number
+		}
+	}
+	return 0
+}
@@ -0,0 +1,213 @@
+package internal
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCheckToolchainConsistency(t *testing.T) {
+	type wantCheck struct {
+		ok       bool
+		line     int
+		expected string
+	}
+
+	installed := func(version string) *ActiveVersion {
+		return &ActiveVersion{Version: version, Source: PathVersionSource, GoRoot: "/usr/local/go", Installed: true}
+	}
+	lockfile := func(requested string, version string) string {
+		return fmt.Sprintf("lock_version = 1\n\n[toolchains.\".go-version\"]\nrequested = %q\nsource = \".go-version\"\nversion = %q\n\n[toolchains.\".go-version\".artifacts.%s]\nurl = \"https://go.dev/dl/%s.tar.gz\"\nsha256 = %q\n",
+			requested, version, tomlKey(CurrentPlatform()), version, strings.Repeat("a", 64))
+	}
+
+	tests := []struct {
+		name      string
+		files     map[string]string
+		active    *ActiveVersion
+		installed map[string]string
+		want      map[string]wantCheck
+	}{
+		{
+			name:   "minor-only pin accepts any patch",
+			files:  map[string]string{GoVersionFile: "# team toolchain\n\n1.25\n"},
+			active: installed("go1.25.5"),
+			want:   map[string]wantCheck{CheckGoVersionFile: {ok: true, line: 3, expected: "go1.25.x"}},
+		},
+		{
+			name:   "exact pin",
+			files:  map[string]string{GoVersionFile: "1.25.3\n"},
+			active: installed("go1.25.5"),
+			want:   map[string]wantCheck{CheckGoVersionFile: {line: 1, expected: "go1.25.3"}},
+		},
+		{
+			name:   "invalid pin",
+			files:  map[string]string{GoVersionFile: "stable\n"},
+			active: installed("go1.25.5"),
+			want:   map[string]wantCheck{CheckGoVersionFile: {line: 1, expected: "a golang version"}},
+		},
+		{
+			name:   "go and toolchain minimums",
+			files:  map[string]string{"go.mod": "module example.com/app\n\ngo 1.24 // minimum\n\ntoolchain go1.25.6\n"},
+			active: installed("go1.25.5"),
+			want: map[string]wantCheck{
+				CheckGoDirective:    {ok: true, line: 3, expected: ">= go1.24"},
+				CheckGoModToolchain: {line: 5, expected: ">= go1.25.6"},
+			},
+		},
+		{
+			name: "minor-only pin allows a newer patch than go.mod requires",
+			files: map[string]string{
+				GoVersionFile: "1.25\n",
+				"go.mod":      "module example.com/app\n\ngo 1.25.3\n",
+			},
+			active: installed("go1.25.5"),
+			want: map[string]wantCheck{
+				CheckGoVersionFile: {ok: true, line: 1, expected: "go1.25.x"},
+				CheckGoDirective:   {ok: true, line: 3, expected: ">= go1.25.3"},
+				CheckPinConflict:   {ok: true, line: 3, expected: ">= go1.25.3"},
+			},
+		},
+		{
+			name: "go.mod requires a newer go than the pin",
+			files: map[string]string{
+				GoVersionFile: "1.24.2\n",
+				"go.mod":      "module example.com/app\n\ngo 1.25.0\n",
+			},
+			active: installed("go1.24.2"),
+			want: map[string]wantCheck{
+				CheckGoVersionFile: {ok: true, line: 1, expected: "go1.24.2"},
+				CheckGoDirective:   {line: 3, expected: ">= go1.25.0"},
+				CheckPinConflict:   {line: 3, expected: ">= go1.25.0"},
+			},
+		},
+		{
+			name:   "no active toolchain",
+			files:  map[string]string{GoVersionFile: "1.25\n"},
+			active: nil,
+			want:   map[string]wantCheck{CheckActive: {expected: "an installed go toolchain"}},
+		},
+		{
+			name:   "pinned toolchain not installed",
+			files:  map[string]string{GoVersionFile: "1.25.5\n"},
+			active: &ActiveVersion{Version: "1.25.5", Source: "/project/.go-version"},
+			want:   map[string]wantCheck{CheckActive: {expected: "1.25.5"}},
+		},
+		{
+			name: "locked version and sha256",
+			files: map[string]string{
+				GoVersionFile: "1.25\n",
+				LockFileName:  lockfile("1.25", "go1.25.5"),
+			},
+			active:    installed("go1.25.5"),
+			installed: map[string]string{"go1.25.5": strings.Repeat("a", 64)},
+			want: map[string]wantCheck{
+				CheckGoVersionFile: {ok: true, line: 1, expected: "go1.25.x"},
+				CheckLockfile:      {ok: true, expected: "up to date"},
+				CheckLockedVersion: {ok: true, line: 3, expected: "go1.25.5"},
+				CheckLockedSHA256:  {ok: true, line: 3, expected: strings.Repeat("a", 64)},
+			},
+		},
+		{
+			name: "locked sha256 mismatch",
+			files: map[string]string{
+				GoVersionFile: "1.25\n",
+				LockFileName:  lockfile("1.25", "go1.25.5"),
+			},
+			active:    installed("go1.25.5"),
+			installed: map[string]string{"go1.25.5": strings.Repeat("b", 64)},
+			want: map[string]wantCheck{
+				CheckGoVersionFile: {ok: true, line: 1, expected: "go1.25.x"},
+				CheckLockfile:      {ok: true, expected: "up to date"},
+				CheckLockedVersion: {ok: true, line: 3, expected: "go1.25.5"},
+				CheckLockedSHA256:  {line: 3, expected: strings.Repeat("a", 64)},
+			},
+		},
+		{
+			name: "other version than locked",
+			files: map[string]string{
+				GoVersionFile: "1.25\n",
+				LockFileName:  lockfile("1.25", "go1.25.5"),
+			},
+			active:    installed("go1.25.4"),
+			installed: map[string]string{"go1.25.4": strings.Repeat("a", 64)},
+			want: map[string]wantCheck{
+				CheckGoVersionFile: {ok: true, line: 1, expected: "go1.25.x"},
+				CheckLockfile:      {ok: true, expected: "up to date"},
+				CheckLockedVersion: {line: 3, expected: "go1.25.5"},
+			},
+		},
+		{
+			name: "outdated lockfile",
+			files: map[string]string{
+				GoVersionFile: "1.25\n",
+				LockFileName:  lockfile("1.24", "go1.24.2"),
+			},
+			active: installed("go1.25.5"),
+			want: map[string]wantCheck{
+				CheckGoVersionFile: {ok: true, line: 1, expected: "go1.25.x"},
+				CheckLockfile:      {expected: "up to date"},
+				CheckLockedVersion: {line: 3, expected: "go1.24.2"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Setenv("HOME", t.TempDir())
+			if test.installed != nil {
+				configPath, err := ConfigFilePath()
+				if err != nil {
+					t.Fatal(err)
+				}
+				if err := os.MkdirAll(filepath.Dir(configPath), 0755); err != nil {
+					t.Fatal(err)
+				}
+				config := &Config{DownloadedVersions: make(map[string]DownloadVersion)}
+				for version, sha := range test.installed {
+					config.DownloadedVersions[version] = DownloadVersion{Version: version, TarPath: "/tmp/" + version + ".tar.gz", SHA256: sha}
+				}
+				if err := config.Save(); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			dir := t.TempDir()
+			for name, content := range test.files {
+				if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			report := CheckToolchainConsistency(dir, test.active)
+
+			wantOK := true
+			for _, want := range test.want {
+				wantOK = wantOK && want.ok
+			}
+			if report.OK != wantOK {
+				t.Errorf("report.OK = %v, want %v", report.OK, wantOK)
+			}
+
+			if len(report.Checks) != len(test.want) {
+				t.Errorf("got %d checks, want %d: %+v", len(report.Checks), len(test.want), report.Checks)
+			}
+			for _, check := range report.Checks {
+				want, ok := test.want[check.Name]
+				if !ok {
+					t.Errorf("unexpected check %+v", check)
+					continue
+				}
+				if check.OK != want.ok || check.Line != want.line || check.Expected != want.expected {
+					t.Errorf("%s = ok %v, line %d, expected %q; want ok %v, line %d, expected %q (%s)",
+						check.Name, check.OK, check.Line, check.Expected, want.ok, want.line, want.expected, check.Message)
+				}
+				if check.Message == "" {
+					t.Errorf("%s has no message", check.Name)
+				}
+			}
+		})
+	}
+}
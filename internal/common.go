@@ -3,8 +3,9 @@ package internal
 import (
 	"fmt"
 	"os/exec"
-	"regexp"
+	"strconv"
 	"strings"
+	"time"
 )
 
 // Utility function to execute shell command and retrieve the shell output.
@@ -35,6 +36,66 @@ func ExecShellCommand(cmd string) ([]byte, error) {
 
 // Utility function to validate golang versions as strings
 func ValidateGoVersion(version string) bool {
-	re := regexp.MustCompile(`^v?(?P<major>0|[1-9]\d*)\.(?P<minor>0|[1-9]\d*)(?:\.(?P<patch>0|[1-9]\d*))?(?:(?P<rc>rc[1-9]\d*))?$`)
-	return re.MatchString(version)
+	_, err := ParseGoVersion(version)
+	return err == nil
+}
+
+// Formats a byte count for humans, e.g. 73400320 -> "70.0 MB".
+func FormatBytes(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+
+	return fmt.Sprintf("%.1f %cB", float64(size)/float64(div), "KMGTPE"[exp])
+}
+
+// Parses an age such as "30d", "12h" or "90m". Besides the units of
+// time.ParseDuration whole days are accepted with the "d" suffix.
+func ParseAge(age string) (time.Duration, error) {
+	age = strings.TrimSpace(age)
+
+	if days, ok := strings.CutSuffix(age, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("Input Error: invalid age '%s'", age)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+
+	duration, err := time.ParseDuration(age)
+	if err != nil {
+		return 0, fmt.Errorf("Input Error: invalid age '%s'", age)
+	}
+
+	return duration, nil
+}
+
+// Parses a size such as "2GB", "500MB" or a plain byte count.
+func ParseSize(size string) (int64, error) {
+	size = strings.ToUpper(strings.TrimSpace(size))
+
+	multiplier := int64(1)
+	for _, suffix := range []struct {
+		unit  string
+		bytes int64
+	}{{"TB", 1 << 40}, {"GB", 1 << 30}, {"MB", 1 << 20}, {"KB", 1 << 10}, {"B", 1}} {
+		if trimmed, ok := strings.CutSuffix(size, suffix.unit); ok {
+			size, multiplier = strings.TrimSpace(trimmed), suffix.bytes
+			break
+		}
+	}
+
+	n, err := strconv.ParseFloat(size, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("Input Error: invalid size '%s'", size)
+	}
+
+	return int64(n * float64(multiplier)), nil
 }
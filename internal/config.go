@@ -1,11 +1,15 @@
 package internal
 
 import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"net/url"
 	"os"
 	"path/filepath"
-	"strings"
+	"sort"
 	"time"
 )
 
@@ -15,6 +19,9 @@ const (
 	ConfigDirName = "gvm"
 	ConfigFile    = "config.json"
 	GoVersionsDir = "go-versions"
+
+	// Number of remote versions kept in the config
+	AvailableVersionsLimit = 10
 )
 
 type Config struct {
@@ -23,6 +30,27 @@ type Config struct {
 	LastRemoteFetch    int64                      `json:"last_remote_fetch"`
 	AvailableVersions  []RemoteVersion            `json:"available_versions"`
 	DownloadedVersions map[string]DownloadVersion `json:"downloaded_versions"`
+	// Remote version source, one of "go.dev", "file", "mirror" or
+	// "github". Empty selects the legacy github scraper.
+	VersionSource string `json:"version_source,omitempty"`
+	// Index file path or mirror url for the "file" and "mirror" sources.
+	VersionSourceURL string `json:"version_source_url,omitempty"`
+	// Proxy, CA, timeout and header settings for network requests
+	HTTP HTTPConfig `json:"http,omitempty"`
+	// Version selected with `gvm use`, used for new shells
+	DefaultVersion string `json:"default_version,omitempty"`
+	// Disables the notice about newer patch releases shown after commands
+	DisableUpdateNotice bool `json:"disable_update_notice,omitempty"`
+	// OSV vulnerability feed used by `gvm audit`, a url or local file.
+	// Empty selects the official go vulnerability database.
+	AdvisoryFeed string `json:"advisory_feed,omitempty"`
+	// Release feed used by `gvm self-update`, empty selects GVM_RELEASES_URL
+	SelfUpdateURL string `json:"self_update_url,omitempty"`
+	// Public key files (minisign or PEM) trusted to sign downloaded
+	// archives. Signatures are looked up as <archive>.minisig or .sig
+	SignatureKeys []string `json:"signature_keys,omitempty"`
+	// Rejects downloads without a valid signature of a trusted key
+	RequireSignature bool `json:"require_signature,omitempty"`
 }
 
 // Path management functions
@@ -76,15 +104,44 @@ func ensureDirectories() error {
 }
 
 func SetupConfig() error {
+	return SetupConfigWithSource("", "")
+}
+
+// Sets up gvm fetching the initial remote versions from the given
+// version source (see NewVersionSource), which is stored in the config.
+func SetupConfigWithSource(sourceKind string, sourceURL string) error {
+	source, err := NewVersionSource(sourceKind, sourceURL)
+	if err != nil {
+		return err
+	}
+
 	if err := ensureDirectories(); err != nil {
 		return err
 	}
 
-	remoteVersions, err := FetchGoVersionsFromGoGithubRelease()
+	remoteVersions, err := source.FetchVersions()
 	if err != nil {
 		return fmt.Errorf("failed to fetch remote versions: %w", err)
 	}
 
+	if len(remoteVersions) > AvailableVersionsLimit {
+		remoteVersions = remoteVersions[:AvailableVersionsLimit]
+	}
+
+	return saveInitialConfig(remoteVersions, time.Now().UnixMilli(), sourceKind, sourceURL)
+}
+
+// Sets up gvm without fetching the remote versions index, for machines
+// without network access. Run `gvm list update` later to fill the index.
+func SetupOfflineConfig() error {
+	if err := ensureDirectories(); err != nil {
+		return err
+	}
+
+	return saveInitialConfig(make([]RemoteVersion, 0), 0, "", "")
+}
+
+func saveInitialConfig(remoteVersions []RemoteVersion, lastRemoteFetch int64, sourceKind string, sourceURL string) error {
 	goDir, err := GoDownloadDir()
 	if err != nil {
 		return err
@@ -93,9 +150,11 @@ func SetupConfig() error {
 	config := &Config{
 		Version:            AppVersion,
 		DownloadPath:       *goDir,
-		LastRemoteFetch:    time.Now().UnixMilli(),
+		LastRemoteFetch:    lastRemoteFetch,
 		AvailableVersions:  remoteVersions,
 		DownloadedVersions: make(map[string]DownloadVersion),
+		VersionSource:      sourceKind,
+		VersionSourceURL:   sourceURL,
 	}
 
 	return config.Save()
@@ -127,49 +186,327 @@ func LoadConfig() (*Config, error) {
 		return nil, fmt.Errorf("failed to parse config file: %w", err)
 	}
 
+	// uses by the shims count once merged on save, but the last used
+	// times apply right away so e.g. prune never sees a stale one
+	journalPath := filepath.Join(filepath.Dir(configPath), UsageJournalFile)
+	for _, path := range []string{journalPath + ".merging", journalPath} {
+		if entries, err := readUsageJournal(path); err == nil {
+			config.applyUsageEntries(entries, false)
+		}
+	}
+
+	ConfigureHTTPClient(config.httpConfig())
+
 	return &config, nil
 }
 
+// Returns the network settings with headers lacking a host scoped to
+// the host of the version source, so e.g. mirror credentials aren't
+// sent to go.dev or the advisory database.
+func (c *Config) httpConfig() HTTPConfig {
+	cfg := c.HTTP
+	host := c.versionSourceHost()
+
+	cfg.Headers = make([]HTTPHeader, 0, len(c.HTTP.Headers))
+	for _, header := range c.HTTP.Headers {
+		if header.Host == "" {
+			// without a known host the header can't be sent safely
+			if host == "" {
+				continue
+			}
+			header.Host = host
+		}
+		cfg.Headers = append(cfg.Headers, header)
+	}
+	return cfg
+}
+
+// Returns the host the configured version source fetches from, empty
+// when the mirror url has none. Index files are read from disk and their
+// archives downloaded from go.dev.
+func (c *Config) versionSourceHost() string {
+	var location string
+	switch c.VersionSource {
+	case "", VersionSourceGitHub:
+		location = GO_GITHUB_RELEASE_URL
+	case VersionSourceMirror:
+		location = c.VersionSourceURL
+	default:
+		location = GO_DEV_DOWNLOAD_URL
+	}
+
+	parsed, err := url.Parse(location)
+	if err != nil {
+		return ""
+	}
+	return parsed.Hostname()
+}
+
+// Returns every downloaded version, newest release first. Names which
+// aren't release versions (linked and devel builds) follow sorted by name.
 func (c *Config) GetDownloadedVersions() *[]DownloadVersion {
-	downloadedVersions := make([]DownloadVersion, 0)
+	downloadedVersions := make([]DownloadVersion, 0, len(c.DownloadedVersions))
+	for _, downloadedVersion := range c.DownloadedVersions {
+		downloadedVersions = append(downloadedVersions, downloadedVersion)
+	}
 
-	for _, version := range c.AvailableVersions {
-		if downloadedVersion, ok := c.DownloadedVersions[version.Version]; ok {
-			downloadedVersions = append(downloadedVersions, downloadedVersion)
+	sort.Slice(downloadedVersions, func(i, j int) bool {
+		a, errA := ParseGoVersion(downloadedVersions[i].Version)
+		b, errB := ParseGoVersion(downloadedVersions[j].Version)
+		switch {
+		case errA == nil && errB == nil:
+			return b.Less(a)
+		case errA == nil || errB == nil:
+			return errA == nil
+		default:
+			return downloadedVersions[i].Version < downloadedVersions[j].Version
 		}
-	}
+	})
 
 	return &downloadedVersions
 }
 
+// Returns the newest stable release of the remote index.
 func (c *Config) GetLTSVersion() (*string, error) {
+	var lts *string
+	var ltsVersion GoVersion
+
 	for _, remote := range c.AvailableVersions {
-		if !strings.Contains(remote.Version, "rc") {
-			return &remote.Version, nil
+		version, err := ParseGoVersion(remote.Version)
+		if err != nil || version.IsPrerelease() {
+			continue
+		}
+		if lts == nil || ltsVersion.Less(version) {
+			lts, ltsVersion = &remote.Version, version
 		}
 	}
 
-	return nil, fmt.Errorf("Config Error: failed to fetch lts from config")
+	if lts == nil {
+		return nil, fmt.Errorf("Config Error: failed to fetch lts from config")
+	}
+
+	return lts, nil
 }
 
+// Writes the whole config, replacing changes other gvm processes made
+// since it was loaded except their usage stats. Changes to an existing
+// config go through UpdateConfig instead.
 func (c *Config) Save() error {
 	configPath, err := ConfigFilePath()
 	if err != nil {
 		return err
 	}
 
+	unlock, err := lockConfig(configPath)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	return c.saveLocked(configPath)
+}
+
+// Writes the config while holding the config lock. Usage stats recorded
+// by other gvm processes since this config was loaded are kept and the
+// usage journal of the shims is merged.
+func (c *Config) saveLocked(configPath string) error {
+	if data, err := os.ReadFile(configPath); err == nil {
+		var onDisk Config
+		if json.Unmarshal(data, &onDisk) == nil {
+			c.mergeUsageStats(&onDisk)
+		}
+	}
+	removeJournal := c.consumeUsageJournal(configPath)
+
 	data, err := json.MarshalIndent(c, "", "  ")
 	if err != nil {
 		return fmt.Errorf("failed to marshal config: %w", err)
 	}
 
-	if err := os.WriteFile(configPath, data, 0644); err != nil {
+	// write to a temp file and rename so readers never see a partial config
+	tmp, err := os.CreateTemp(filepath.Dir(configPath), ".config-")
+	if err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
 		return fmt.Errorf("failed to write config file: %w", err)
 	}
 
+	if err := os.Rename(tmp.Name(), configPath); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+	removeJournal()
+
+	// best effort, the shims rebuild a missing index from the config
+	c.WriteShimIndex()
+
 	return nil
 }
 
+// Keeps the newest usage stats of every version known to both configs.
+func (c *Config) mergeUsageStats(other *Config) {
+	for name, theirs := range other.DownloadedVersions {
+		ours, exists := c.DownloadedVersions[name]
+		if !exists {
+			continue
+		}
+
+		if theirs.LastUsedAt > ours.LastUsedAt {
+			ours.LastUsedAt = theirs.LastUsedAt
+		}
+		if theirs.UseCount > ours.UseCount {
+			ours.UseCount = theirs.UseCount
+		}
+		if ours.InstalledAt == 0 {
+			ours.InstalledAt = theirs.InstalledAt
+		}
+		c.DownloadedVersions[name] = ours
+	}
+}
+
+// Loads the latest config, applies update and saves it, all while
+// holding the config lock so concurrent gvm processes can't interleave.
+func UpdateConfig(update func(*Config) error) (*Config, error) {
+	configPath, err := ConfigFilePath()
+	if err != nil {
+		return nil, err
+	}
+
+	unlock, err := lockConfig(configPath)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	config, err := LoadConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := update(config); err != nil {
+		return nil, err
+	}
+
+	if err := config.saveLocked(configPath); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+// Applies change to the latest config on disk while holding the config
+// lock and replaces c with the result, so edits made by other gvm
+// processes since c was loaded aren't overwritten.
+func (c *Config) update(change func(*Config) error) error {
+	latest, err := UpdateConfig(change)
+	if err != nil {
+		return err
+	}
+
+	*c = *latest
+	return nil
+}
+
+// Records a use of the version (e.g. by `gvm use`) in the config.
+func RecordVersionUsage(version string) error {
+	_, err := UpdateConfig(func(c *Config) error {
+		c.MarkVersionUsed(version, time.Now())
+		return nil
+	})
+	return err
+}
+
+// Updates the last used time and use count of a downloaded version.
+func (c *Config) MarkVersionUsed(version string, now time.Time) {
+	downloadVersion := c.ResolveDownloadedVersion(version)
+	if downloadVersion == nil {
+		return
+	}
+
+	downloadVersion.LastUsedAt = now.UnixMilli()
+	downloadVersion.UseCount++
+	c.DownloadedVersions[downloadVersion.Version] = *downloadVersion
+}
+
+// How long a config lock may be held before it is considered stale,
+// e.g. left behind by a killed process.
+const configLockStaleAfter = 10 * time.Second
+
+// Takes an exclusive lock file next to the config. The lock file holds
+// the pid and a random nonce of its owner, so releasing it never removes
+// a lock another process took after breaking this one as stale. Returns
+// the function releasing it.
+func lockConfig(configPath string) (func(), error) {
+	lockPath := configPath + ".lock"
+	deadline := time.Now().Add(2 * configLockStaleAfter)
+
+	nonce := make([]byte, 8)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to lock config file: %w", err)
+	}
+	id := hex.EncodeToString(nonce)
+	owner := fmt.Sprintf("%d %s\n", os.Getpid(), id)
+
+	for {
+		lock, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+		if err == nil {
+			_, err = lock.WriteString(owner)
+			if closeErr := lock.Close(); err == nil {
+				err = closeErr
+			}
+			if err != nil {
+				os.Remove(lockPath)
+				return nil, fmt.Errorf("failed to lock config file: %w", err)
+			}
+
+			return func() {
+				if data, err := os.ReadFile(lockPath); err == nil && string(data) == owner {
+					os.Remove(lockPath)
+				}
+			}, nil
+		}
+		if !os.IsExist(err) {
+			return nil, fmt.Errorf("failed to lock config file: %w", err)
+		}
+
+		if info, err := os.Stat(lockPath); err == nil && time.Since(info.ModTime()) > configLockStaleAfter {
+			if stale, err := os.ReadFile(lockPath); err == nil {
+				breakStaleLock(lockPath, stale, lockPath+".stale-"+id)
+			}
+			continue
+		}
+
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("failed to lock config file: %s is held by another gvm process", lockPath)
+		}
+		time.Sleep(25 * time.Millisecond)
+	}
+}
+
+// Removes a stale lock file still holding the contents observed as
+// stale. It is moved aside first, so a lock another process took since
+// (e.g. after breaking the same stale lock) is put back instead.
+func breakStaleLock(lockPath string, stale []byte, aside string) {
+	if err := os.Rename(lockPath, aside); err != nil {
+		return
+	}
+	defer os.Remove(aside)
+
+	if data, err := os.ReadFile(aside); err == nil && !bytes.Equal(data, stale) {
+		// fails when yet another process holds the lock by now
+		os.Link(aside, lockPath)
+	}
+}
+
 // Config operations
 func (c *Config) MarkVersionAsDownloaded(remoteVersion *RemoteVersion, tarballPath string) error {
 	if remoteVersion == nil {
@@ -180,57 +517,98 @@ func (c *Config) MarkVersionAsDownloaded(remoteVersion *RemoteVersion, tarballPa
 		return nil
 	}
 
-	c.DownloadedVersions[remoteVersion.Version] = DownloadVersion{
-		Version: remoteVersion.Version,
-		TarPath: tarballPath,
+	checksum := remoteVersion.SHA256
+	if checksum == "" {
+		// best effort, only used to find the archive in the download cache
+		checksum, _ = FileSHA256(tarballPath)
 	}
 
-	if err := c.Save(); err != nil {
-		return err
+	// replaces an entry callers removed from c, e.g. one whose files went missing
+	return c.update(func(latest *Config) error {
+		if latest.DownloadedVersions == nil {
+			latest.DownloadedVersions = make(map[string]DownloadVersion)
+		}
+
+		latest.DownloadedVersions[remoteVersion.Version] = DownloadVersion{
+			Version:     remoteVersion.Version,
+			TarPath:     tarballPath,
+			SHA256:      checksum,
+			InstalledAt: time.Now().UnixMilli(),
+		}
+		return nil
+	})
+}
+
+// Registers an already built DownloadVersion (e.g. a linked external
+// installation), replacing any previous entry with the same version.
+func (c *Config) AddDownloadedVersion(downloadVersion DownloadVersion) error {
+	if downloadVersion.InstalledAt == 0 {
+		downloadVersion.InstalledAt = time.Now().UnixMilli()
 	}
 
-	if err := c.Save(); err != nil {
+	return c.update(func(latest *Config) error {
+		if latest.DownloadedVersions == nil {
+			latest.DownloadedVersions = make(map[string]DownloadVersion)
+		}
+
+		latest.DownloadedVersions[downloadVersion.Version] = downloadVersion
+		return nil
+	})
+}
+
+// Returns the remote version source selected in the config.
+func (c *Config) GetVersionSource() (VersionSource, error) {
+	return NewVersionSource(c.VersionSource, c.VersionSourceURL)
+}
+
+func (c *Config) UpdateAvailableVersions() error {
+	source, err := c.GetVersionSource()
+	if err != nil {
 		return err
 	}
 
-	return nil
+	return c.UpdateAvailableVersionsFrom(source)
 }
 
-func (c *Config) UpdateAvailableVersions() error {
-	newVersions, err := FetchGoVersionsFromGoGithubRelease()
+// Refreshes the available versions from the given source instead of
+// the configured one.
+func (c *Config) UpdateAvailableVersionsFrom(source VersionSource) error {
+	newVersions, err := source.FetchVersions()
 	if err != nil {
 		return fmt.Errorf("failed to fetch new versions: %w", err)
 	}
 
 	// Keep only top 10 versions
-	limit := 10
+	limit := AvailableVersionsLimit
 	if len(newVersions) > limit {
 		newVersions = newVersions[:limit]
 	}
 
-	// Create a set of existing versions for quick lookup
-	existingSet := make(map[string]bool)
-	for _, v := range c.AvailableVersions {
-		existingSet[v.Version] = true
-	}
+	return c.update(func(latest *Config) error {
+		// Create a set of existing versions for quick lookup
+		existingSet := make(map[string]bool)
+		for _, v := range latest.AvailableVersions {
+			existingSet[v.Version] = true
+		}
 
-	// Add only new versions
-	var latestVersions []RemoteVersion
-	for _, v := range newVersions {
-		if !existingSet[v.Version] {
-			latestVersions = append(latestVersions, v)
+		// Add only new versions
+		var latestVersions []RemoteVersion
+		for _, v := range newVersions {
+			if !existingSet[v.Version] {
+				latestVersions = append(latestVersions, v)
+			}
 		}
-	}
 
-	// Add existing versions to fill up to limit
-	for _, v := range c.AvailableVersions {
-		if len(latestVersions) >= limit {
-			break
+		// Add existing versions to fill up to limit
+		for _, v := range latest.AvailableVersions {
+			if len(latestVersions) >= limit {
+				break
+			}
+			latestVersions = append(latestVersions, v)
 		}
-		latestVersions = append(latestVersions, v)
-	}
 
-	c.LastRemoteFetch = time.Now().UnixMilli()
-	c.AvailableVersions = latestVersions
-	return c.Save()
+		latest.LastRemoteFetch = time.Now().UnixMilli()
+		latest.AvailableVersions = latestVersions
+		return nil
+	})
 }
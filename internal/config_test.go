@@ -0,0 +1,129 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type staticVersionSource []RemoteVersion
+
+func (s staticVersionSource) Name() string {
+	return "static"
+}
+
+func (s staticVersionSource) FetchVersions() ([]RemoteVersion, error) {
+	return s, nil
+}
+
+func TestConfigMutatorsKeepConcurrentEdits(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	configPath, err := ConfigFilePath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Dir(configPath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	initial := &Config{DownloadedVersions: map[string]DownloadVersion{
+		"system": {Version: "system", LinkPath: "/usr/lib/go"},
+	}}
+	if err := initial.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	// two gvm processes working on the config loaded at the same time
+	first, err := LoadConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := LoadConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := first.MarkVersionAsDownloaded(&RemoteVersion{Version: "go1.25.5", SHA256: "abc"}, "/tmp/go1.25.5.tar.gz"); err != nil {
+		t.Fatal(err)
+	}
+	if err := second.AddDownloadedVersion(DownloadVersion{Version: "go1.22.0", LinkPath: "/opt/go1.22"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := first.UpdateAvailableVersionsFrom(staticVersionSource{{Version: "go1.25.5"}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := second.RemoveDownloadedVersion("system"); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, version := range []string{"go1.25.5", "go1.22.0"} {
+		if _, ok := loaded.DownloadedVersions[version]; !ok {
+			t.Errorf("%s was lost, config has %v", version, loaded.DownloadedVersions)
+		}
+	}
+	if _, ok := loaded.DownloadedVersions["system"]; ok {
+		t.Error("removed version system is still in the config")
+	}
+	if len(loaded.AvailableVersions) != 1 || loaded.LastRemoteFetch == 0 {
+		t.Errorf("available versions = %v, last fetch %d", loaded.AvailableVersions, loaded.LastRemoteFetch)
+	}
+
+	// the mutators leave the callers with the latest config
+	if _, ok := second.DownloadedVersions["go1.25.5"]; !ok || len(second.AvailableVersions) != 1 {
+		t.Errorf("second config wasn't refreshed: %v", second.DownloadedVersions)
+	}
+}
+
+func TestLockConfigOwnership(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), ConfigFile)
+	lockPath := configPath + ".lock"
+
+	// a stale lock left behind by a killed process is broken
+	if err := os.WriteFile(lockPath, []byte("1 dead\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-2 * configLockStaleAfter)
+	if err := os.Chtimes(lockPath, old, old); err != nil {
+		t.Fatal(err)
+	}
+	unlock, err := lockConfig(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	unlock()
+	if _, err := os.Stat(lockPath); !os.IsNotExist(err) {
+		t.Fatalf("lock not released: %v", err)
+	}
+
+	// releasing a lock another process took over leaves it alone
+	unlock, err = lockConfig(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(lockPath, []byte("2 other\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	unlock()
+	if data, err := os.ReadFile(lockPath); err != nil || string(data) != "2 other\n" {
+		t.Fatalf("lock of another process = %q, %v", data, err)
+	}
+
+	// a lock taken since the stale one was observed is put back
+	breakStaleLock(lockPath, []byte("1 dead\n"), lockPath+".stale-test")
+	if data, err := os.ReadFile(lockPath); err != nil || string(data) != "2 other\n" {
+		t.Fatalf("lock taken by another process = %q, %v", data, err)
+	}
+	if _, err := os.Stat(lockPath + ".stale-test"); !os.IsNotExist(err) {
+		t.Errorf("moved aside lock left behind: %v", err)
+	}
+
+	breakStaleLock(lockPath, []byte("2 other\n"), lockPath+".stale-test")
+	if _, err := os.Stat(lockPath); !os.IsNotExist(err) {
+		t.Errorf("stale lock not removed: %v", err)
+	}
+}
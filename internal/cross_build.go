@@ -0,0 +1,159 @@
+package internal
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Name of the checksum file written next to cross compiled artifacts.
+const ChecksumFile = "SHA256SUMS"
+
+// A GOOS/GOARCH pair to build for.
+type BuildTarget struct {
+	GOOS   string
+	GOARCH string
+}
+
+func (t BuildTarget) String() string {
+	return fmt.Sprintf("%s/%s", t.GOOS, t.GOARCH)
+}
+
+// An artifact produced by CrossBuild.
+type BuildArtifact struct {
+	Target BuildTarget
+	// path of the binary relative to the output directory
+	Path   string
+	SHA256 string
+}
+
+// Parses a comma separated target matrix such as
+// "linux/amd64,darwin/arm64".
+func ParseBuildTargets(spec string) ([]BuildTarget, error) {
+	targets := make([]BuildTarget, 0)
+	seen := make(map[string]bool)
+
+	for _, raw := range strings.Split(spec, ",") {
+		raw = strings.TrimSpace(raw)
+		if raw == "" {
+			continue
+		}
+
+		goos, goarch, ok := strings.Cut(raw, "/")
+		if !ok || goos == "" || goarch == "" || strings.Contains(goarch, "/") {
+			return nil, fmt.Errorf("Input Error: invalid build target '%s', expected <goos>/<goarch>", raw)
+		}
+
+		if seen[raw] {
+			continue
+		}
+		seen[raw] = true
+		targets = append(targets, BuildTarget{GOOS: goos, GOARCH: goarch})
+	}
+
+	if len(targets) == 0 {
+		return nil, fmt.Errorf("Input Error: no build targets given")
+	}
+
+	return targets, nil
+}
+
+// Returns the name of the binaries built from pkg: name when given,
+// otherwise the base name of the package directory. Patterns like
+// "./..." match several packages and need an explicit name.
+func BuildBinaryName(pkg string, name string) (string, error) {
+	if name != "" {
+		return name, nil
+	}
+	if strings.Contains(pkg, "...") {
+		return "", fmt.Errorf("Input Error: package pattern '%s' matches several packages, pass the binary name with --name", pkg)
+	}
+
+	absPkg, err := filepath.Abs(pkg)
+	if err != nil {
+		return "", fmt.Errorf("Input Error: %w", err)
+	}
+	return filepath.Base(absPkg), nil
+}
+
+// Runs `go build` of pkg with goroot's toolchain once per target and
+// writes the binaries to <outDir>/<goos>_<goarch>/<name>, followed by a
+// SHA256SUMS file covering every artifact.
+func CrossBuild(goroot string, targets []BuildTarget, pkg string, name string, outDir string, extraArgs []string) ([]BuildArtifact, error) {
+	artifacts := make([]BuildArtifact, 0, len(targets))
+	goBinary := filepath.Join(goroot, "bin", "go")
+
+	for _, target := range targets {
+		binaryName := name
+		if target.GOOS == "windows" {
+			binaryName += ".exe"
+		}
+
+		relPath := filepath.Join(fmt.Sprintf("%s_%s", target.GOOS, target.GOARCH), binaryName)
+		artifactPath := filepath.Join(outDir, relPath)
+
+		if err := os.MkdirAll(filepath.Dir(artifactPath), 0755); err != nil {
+			return nil, fmt.Errorf("Build Error: %w", err)
+		}
+
+		env := append(ToolchainEnv(goroot, os.Environ()),
+			fmt.Sprintf("GOOS=%s", target.GOOS),
+			fmt.Sprintf("GOARCH=%s", target.GOARCH),
+		)
+		if !hasEnv(env, "CGO_ENABLED") {
+			env = append(env, "CGO_ENABLED=0")
+		}
+
+		args := append([]string{"build", "-o", artifactPath}, extraArgs...)
+		args = append(args, pkg)
+
+		if err := runCommand("", env, goBinary, args...); err != nil {
+			return nil, fmt.Errorf("Build Error: go build for %s failed: %w", target, err)
+		}
+
+		checksum, err := FileSHA256(artifactPath)
+		if err != nil {
+			return nil, fmt.Errorf("Build Error: failed to hash %s: %w", artifactPath, err)
+		}
+
+		artifacts = append(artifacts, BuildArtifact{
+			Target: target,
+			Path:   relPath,
+			SHA256: checksum,
+		})
+	}
+
+	if err := WriteChecksumFile(outDir, artifacts); err != nil {
+		return nil, err
+	}
+
+	return artifacts, nil
+}
+
+// Writes the artifacts checksums to <outDir>/SHA256SUMS in the format
+// understood by `sha256sum -c`.
+func WriteChecksumFile(outDir string, artifacts []BuildArtifact) error {
+	lines := make([]string, 0, len(artifacts))
+	for _, artifact := range artifacts {
+		lines = append(lines, fmt.Sprintf("%s  %s", artifact.SHA256, filepath.ToSlash(artifact.Path)))
+	}
+	sort.Strings(lines)
+
+	content := strings.Join(lines, "\n") + "\n"
+	if err := os.WriteFile(filepath.Join(outDir, ChecksumFile), []byte(content), 0644); err != nil {
+		return fmt.Errorf("Build Error: failed to write checksums: %w", err)
+	}
+
+	return nil
+}
+
+func hasEnv(env []string, key string) bool {
+	for _, entry := range env {
+		if strings.HasPrefix(entry, key+"=") {
+			return true
+		}
+	}
+	return false
+}
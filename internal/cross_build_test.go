@@ -0,0 +1,73 @@
+package internal
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseBuildTargets(t *testing.T) {
+	tests := []struct {
+		spec    string
+		want    []BuildTarget
+		wantErr bool
+	}{
+		{spec: "linux/amd64", want: []BuildTarget{{GOOS: "linux", GOARCH: "amd64"}}},
+		{spec: " linux/amd64 , darwin/arm64,", want: []BuildTarget{{GOOS: "linux", GOARCH: "amd64"}, {GOOS: "darwin", GOARCH: "arm64"}}},
+		{spec: "linux/amd64,linux/amd64", want: []BuildTarget{{GOOS: "linux", GOARCH: "amd64"}}},
+		{spec: "", wantErr: true},
+		{spec: " , ", wantErr: true},
+		{spec: "linux", wantErr: true},
+		{spec: "linux/", wantErr: true},
+		{spec: "/amd64", wantErr: true},
+		{spec: "linux/amd64/v2", wantErr: true},
+	}
+
+	for _, test := range tests {
+		got, err := ParseBuildTargets(test.spec)
+		if test.wantErr {
+			if err == nil {
+				t.Errorf("ParseBuildTargets(%q) = %v, want error", test.spec, got)
+			}
+			continue
+		}
+		if err != nil || !reflect.DeepEqual(got, test.want) {
+			t.Errorf("ParseBuildTargets(%q) = %v, %v, want %v", test.spec, got, err, test.want)
+		}
+	}
+}
+
+func TestBuildBinaryName(t *testing.T) {
+	cwd, err := filepath.Abs(".")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		pkg     string
+		name    string
+		want    string
+		wantErr bool
+	}{
+		{pkg: ".", want: filepath.Base(cwd)},
+		{pkg: "./cmd/server", want: "server"},
+		{pkg: "./cmd/server/", want: "server"},
+		{pkg: "./cmd/server", name: "api", want: "api"},
+		{pkg: "./...", wantErr: true},
+		{pkg: "./cmd/...", wantErr: true},
+		{pkg: "./...", name: "tool", want: "tool"},
+	}
+
+	for _, test := range tests {
+		got, err := BuildBinaryName(test.pkg, test.name)
+		if test.wantErr {
+			if err == nil {
+				t.Errorf("BuildBinaryName(%q, %q) = %q, want error", test.pkg, test.name, got)
+			}
+			continue
+		}
+		if err != nil || got != test.want {
+			t.Errorf("BuildBinaryName(%q, %q) = %q, %v, want %q", test.pkg, test.name, got, err, test.want)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package internal
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// Source of the active version when the go found in PATH isn't a gvm
+// shim, e.g. the /usr/local/go installation of `gvm use`.
+const PathVersionSource = "PATH"
+
+// The go version a `go` command run in a directory would use and why.
+type ActiveVersion struct {
+	// version name, e.g. "go1.25.5", or the requested version when it
+	// isn't installed
+	Version string `json:"version"`
+	// GVM_GO_VERSION, the path of a .go-version or go.mod file, "default"
+	// or "PATH"
+	Source string `json:"source"`
+	// GOROOT of the toolchain, empty when the version isn't installed
+	GoRoot string `json:"goroot,omitempty"`
+	// whether the requested version is installed
+	Installed bool `json:"installed"`
+}
+
+// Determines the go version active in dir. When the go found in PATH is
+// a gvm shim the version is resolved like the shim does (environment,
+// .go-version, go.mod, default), otherwise it is the version of the go
+// installation found in PATH or, lacking one, the global /usr/local/go.
+// The version is read from files, no go command is run unless the
+// installation has no VERSION file. Returns nil when no go is available.
+func ResolveActiveVersion(dir string) (*ActiveVersion, error) {
+	goPath := findGoBinary()
+	if goPath == "" {
+		return nil, nil
+	}
+
+	if isShim(goPath) {
+		index, err := LoadShimIndex()
+		if err != nil {
+			return nil, err
+		}
+
+		request := ResolveVersionRequest(dir, index.Default)
+		if request == nil {
+			return nil, nil
+		}
+
+		name, goroot, ok := index.Resolve(request)
+		if !ok {
+			return &ActiveVersion{Version: request.Version, Source: request.Source}, nil
+		}
+		return &ActiveVersion{Version: name, Source: request.Source, GoRoot: goroot, Installed: true}, nil
+	}
+
+	goroot := filepath.Dir(filepath.Dir(goPath))
+	version, err := DetectGoRootVersion(goroot)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ActiveVersion{Version: version, Source: PathVersionSource, GoRoot: goroot, Installed: true}, nil
+}
+
+// Version number without the "go" prefix, e.g. "1.25.5". Names which
+// aren't versions are returned unchanged.
+func (a *ActiveVersion) Number() string {
+	if version, err := ParseGoVersion(a.Version); err == nil {
+		return version.Number()
+	}
+	return a.Version
+}
+
+// Fills the placeholders of a `gvm prompt` format: {version}, {number},
+// {source} (the file name of project sources) and {missing} ("!" when
+// the version isn't installed).
+func (a *ActiveVersion) FormatPrompt(format string) string {
+	source := a.Source
+	if filepath.IsAbs(source) {
+		source = filepath.Base(source)
+	}
+
+	missing := ""
+	if !a.Installed {
+		missing = "!"
+	}
+
+	return strings.NewReplacer(
+		"{version}", a.Version,
+		"{number}", a.Number(),
+		"{source}", source,
+		"{missing}", missing,
+	).Replace(format)
+}
+
+// Returns the absolute path of a tool (e.g. "go" or "gofmt") of the
+// active toolchain. Tarball toolchains are extracted when necessary.
+func (a *ActiveVersion) ToolPath(tool string) (string, error) {
+	if !a.Installed {
+		return "", fmt.Errorf("Toolchain Error: Go %s requested by %s is not installed", a.Version, a.Source)
+	}
+
+	goroot, err := PrepareGoRoot(a.Version, a.GoRoot)
+	if err != nil {
+		return "", err
+	}
+
+	toolPath := filepath.Join(goroot, "bin", tool)
+	if runtime.GOOS == "windows" && !strings.HasSuffix(toolPath, ".exe") {
+		toolPath += ".exe"
+	}
+	if _, err := os.Stat(toolPath); err != nil {
+		return "", fmt.Errorf("Toolchain Error: %s not found in %s", tool, goroot)
+	}
+
+	return filepath.Abs(toolPath)
+}
+
+// Reports whether the (symlink resolved) go binary is a gvm shim.
+func isShim(goPath string) bool {
+	shimsDir, err := ShimsDir()
+	if err != nil {
+		return false
+	}
+	if resolved, err := filepath.EvalSymlinks(shimsDir); err == nil {
+		shimsDir = resolved
+	}
+	return filepath.Dir(goPath) == filepath.Clean(shimsDir)
+}
@@ -0,0 +1,155 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+)
+
+func TestResolveActiveVersion(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake go installations are shell scripts")
+	}
+
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv(VersionEnvVar, "")
+
+	toolchains := t.TempDir()
+	goroot125 := filepath.Join(toolchains, "go1.25.5")
+	goroot124 := filepath.Join(toolchains, "go1.24.2")
+	writeFakeGoRoot(t, goroot125, "go1.25.5")
+	writeFakeGoRoot(t, goroot124, "go1.24.2")
+
+	config := &Config{
+		DefaultVersion: "1.24",
+		DownloadedVersions: map[string]DownloadVersion{
+			"go1.25.5": {Version: "go1.25.5", LinkPath: goroot125},
+			"go1.24.2": {Version: "go1.24.2", LinkPath: goroot124},
+		},
+	}
+	if err := config.WriteShimIndex(); err != nil {
+		t.Fatal(err)
+	}
+
+	shimsDir, err := ShimsDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(shimsDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(shimsDir, "go"), []byte("#!/bin/sh\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	// a go installation outside of gvm, found in PATH through a symlink
+	pathGoRoot := filepath.Join(t.TempDir(), "go")
+	writeFakeGoRoot(t, pathGoRoot, "go1.22.3")
+	if err := os.WriteFile(filepath.Join(pathGoRoot, "VERSION"), []byte("go1.22.3\ntime 2025-04-01T17:09:23Z\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	pathBin := t.TempDir()
+	if err := os.Symlink(filepath.Join(pathGoRoot, "bin", "go"), filepath.Join(pathBin, "go")); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		path  string
+		env   string
+		files map[string]string
+		want  ActiveVersion
+	}{
+		{
+			name:  "shim with GVM_GO_VERSION",
+			path:  shimsDir,
+			env:   "1.25.5",
+			files: map[string]string{GoVersionFile: "1.24\n"},
+			want:  ActiveVersion{Version: "go1.25.5", Source: VersionEnvVar, GoRoot: goroot125, Installed: true},
+		},
+		{
+			name:  "shim with .go-version",
+			path:  shimsDir,
+			files: map[string]string{GoVersionFile: "1.25\n"},
+			want:  ActiveVersion{Version: "go1.25.5", Source: GoVersionFile, GoRoot: goroot125, Installed: true},
+		},
+		{
+			name:  "shim with go.mod",
+			path:  shimsDir,
+			files: map[string]string{"go.mod": "module example.com/app\n\ngo 1.24.0\n"},
+			want:  ActiveVersion{Version: "go1.24.2", Source: "go.mod", GoRoot: goroot124, Installed: true},
+		},
+		{
+			name: "shim with default",
+			path: shimsDir,
+			want: ActiveVersion{Version: "go1.24.2", Source: DefaultVersionSource, GoRoot: goroot124, Installed: true},
+		},
+		{
+			name:  "shim with a version that isn't installed",
+			path:  shimsDir,
+			files: map[string]string{GoVersionFile: "1.23.4\n"},
+			want:  ActiveVersion{Version: "1.23.4", Source: GoVersionFile},
+		},
+		{
+			name:  "go in PATH ignores the project",
+			path:  pathBin,
+			files: map[string]string{GoVersionFile: "1.25\n"},
+			want:  ActiveVersion{Version: "go1.22.3", Source: PathVersionSource, GoRoot: pathGoRoot, Installed: true},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Setenv("PATH", test.path)
+			t.Setenv(VersionEnvVar, test.env)
+
+			dir := t.TempDir()
+			for name, content := range test.files {
+				if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			// project files are reported by path
+			want := test.want
+			if _, ok := test.files[want.Source]; ok {
+				want.Source = filepath.Join(dir, want.Source)
+			}
+
+			got, err := ResolveActiveVersion(dir)
+			if err != nil {
+				t.Fatalf("ResolveActiveVersion() = %v", err)
+			}
+			if got == nil || !reflect.DeepEqual(*got, want) {
+				t.Errorf("ResolveActiveVersion() = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestActiveVersionFormatPrompt(t *testing.T) {
+	installed := &ActiveVersion{Version: "go1.25.5", Source: "/project/.go-version", GoRoot: "/opt/go", Installed: true}
+	missing := &ActiveVersion{Version: "1.26rc1", Source: VersionEnvVar}
+	linked := &ActiveVersion{Version: "system", Source: DefaultVersionSource, GoRoot: "/usr/lib/go", Installed: true}
+
+	tests := []struct {
+		active *ActiveVersion
+		format string
+		want   string
+	}{
+		{active: installed, format: "go {number}{missing}", want: "go 1.25.5"},
+		{active: installed, format: "{version} ({source})", want: "go1.25.5 (.go-version)"},
+		{active: missing, format: "go {number}{missing}", want: "go 1.26rc1!"},
+		{active: missing, format: "{version} from {source}", want: "1.26rc1 from GVM_GO_VERSION"},
+		{active: linked, format: "{number}/{version}/{source}", want: "system/system/default"},
+		{active: installed, format: "{number}{number} {unknown}", want: "1.25.51.25.5 {unknown}"},
+		{active: installed, format: "", want: ""},
+	}
+
+	for _, test := range tests {
+		if got := test.active.FormatPrompt(test.format); got != test.want {
+			t.Errorf("FormatPrompt(%q) of %s = %q, want %q", test.format, test.active.Version, got, test.want)
+		}
+	}
+}
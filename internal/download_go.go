@@ -2,6 +2,8 @@ package internal
 
 import (
 	"fmt"
+	"os"
+	"os/exec"
 	"path/filepath"
 	"strings"
 )
@@ -13,6 +15,125 @@ type DownloadVersion struct {
 	Version string `json:"version"`
 	// path to downloaded golang tarball.
 	TarPath string `json:"tar_path"`
+	// sha256 of the tarball, its key in the download cache
+	SHA256 string `json:"sha256,omitempty"`
+	// GOROOT of an externally installed golang registered
+	// with `gvm link`. Empty for versions backed by a tarball.
+	LinkPath string `json:"link_path,omitempty"`
+	// unix milliseconds of the installation
+	InstalledAt int64 `json:"installed_at,omitempty"`
+	// unix milliseconds of the last `gvm use` or `gvm build`
+	LastUsedAt int64 `json:"last_used_at,omitempty"`
+	UseCount   int   `json:"use_count,omitempty"`
+}
+
+// Reports whether the version is an external installation
+// rather than a tarball managed by gvm.
+func (dv *DownloadVersion) IsLink() bool {
+	return dv.LinkPath != ""
+}
+
+// Makes the version available as <destDir>/go, either by extracting
+// its tarball or by symlinking the external GOROOT.
+func (dv *DownloadVersion) InstallInto(destDir string) error {
+	if !dv.IsLink() {
+		return ExtractArchive(dv.TarPath, destDir)
+	}
+
+	if _, err := ReadGoRootVersion(dv.LinkPath); err != nil {
+		return err
+	}
+
+	target := filepath.Join(destDir, "go")
+	if err := os.Symlink(dv.LinkPath, target); err != nil {
+		return fmt.Errorf("Link Error: failed to link %s to %s: %w", target, dv.LinkPath, err)
+	}
+
+	return nil
+}
+
+// Validates goroot by running its `bin/go version` and returns the
+// reported version (e.g. "go1.22.2").
+func ReadGoRootVersion(goroot string) (string, error) {
+	goBinary := filepath.Join(goroot, "bin", "go")
+
+	out, err := exec.Command(goBinary, "version").Output()
+	if err != nil {
+		return "", fmt.Errorf("Link Error: %s is not a valid go installation: %w", goroot, err)
+	}
+
+	// go version go1.22.2 linux/amd64
+	fields := strings.Fields(string(out))
+	if len(fields) < 3 || fields[0] != "go" || fields[1] != "version" {
+		return "", fmt.Errorf("Link Error: unexpected output of %s version: %q", goBinary, strings.TrimSpace(string(out)))
+	}
+
+	return fields[2], nil
+}
+
+// Builds the DownloadVersion for an external golang installation at
+// goroot, validated through its `bin/go version`. The installation of
+// `gvm use` is refused, since switching versions purges it. Returns the
+// version of the installation as well.
+func NewLinkedVersion(name string, goroot string) (*DownloadVersion, string, error) {
+	if err := validateLinkName(name); err != nil {
+		return nil, "", err
+	}
+
+	absRoot, err := filepath.Abs(goroot)
+	if err != nil {
+		return nil, "", fmt.Errorf("Link Error: %w", err)
+	}
+
+	if isInGlobalGoRoot(absRoot) {
+		return nil, "", fmt.Errorf("Link Error: %s is the installation managed by 'gvm use' and is removed when switching versions. Link a copy outside of %s instead.", goroot, globalGoRoot)
+	}
+
+	goVersion, err := ReadGoRootVersion(absRoot)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return &DownloadVersion{
+		Version:  name,
+		LinkPath: absRoot,
+	}, goVersion, nil
+}
+
+// Checks that a link name can be stored next to the downloaded versions:
+// it ends up in paths and the line based shim index, and must not be
+// mistaken for a release version.
+func validateLinkName(name string) error {
+	if strings.TrimSpace(name) == "" {
+		return fmt.Errorf("Link Error: the link name must not be empty")
+	}
+	if strings.ContainsAny(name, "/\\\t\n\r") {
+		return fmt.Errorf("Link Error: link name %q must not contain slashes, tabs or newlines", name)
+	}
+	if _, err := ParseGoVersion(name); err == nil {
+		return fmt.Errorf("Link Error: link name '%s' is a golang version, pick a name like 'system-%s' instead", name, strings.TrimPrefix(strings.TrimSpace(name), "go"))
+	}
+	return nil
+}
+
+// Reports whether path, with symlinks resolved, lies within the global
+// installation, resolved as well.
+func isInGlobalGoRoot(path string) bool {
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		path = resolved
+	}
+
+	roots := []string{globalGoRoot}
+	if resolved, err := filepath.EvalSymlinks(globalGoRoot); err == nil {
+		roots = append(roots, resolved)
+	}
+
+	for _, root := range roots {
+		if isWithinRoot(root, path) {
+			return true
+		}
+	}
+	return false
 }
 
 func (dv *DownloadVersion) GetDecompressedDirName() string {
@@ -0,0 +1,101 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// Creates a goroot whose bin/go reports version.
+func writeFakeGoRoot(t *testing.T, goroot string, version string) {
+	t.Helper()
+
+	if err := os.MkdirAll(filepath.Join(goroot, "bin"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	script := "#!/bin/sh\necho go version " + version + " linux/amd64\n"
+	if err := os.WriteFile(filepath.Join(goroot, "bin", "go"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNewLinkedVersionRejectsGlobalGoRoot(t *testing.T) {
+	dir := t.TempDir()
+
+	previous := globalGoRoot
+	globalGoRoot = filepath.Join(dir, "usr", "local", "go")
+	t.Cleanup(func() { globalGoRoot = previous })
+
+	writeFakeGoRoot(t, globalGoRoot, "go1.25.5")
+	external := filepath.Join(dir, "opt", "go")
+	writeFakeGoRoot(t, external, "go1.24.2")
+
+	alias := filepath.Join(dir, "alias")
+	if err := os.Symlink(globalGoRoot, alias); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		goroot  string
+		wantErr bool
+	}{
+		{name: "global installation", goroot: globalGoRoot, wantErr: true},
+		{name: "unclean path into global installation", goroot: globalGoRoot + "/bin/..", wantErr: true},
+		{name: "symlink to global installation", goroot: alias, wantErr: true},
+		{name: "external installation", goroot: external},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			linked, goVersion, err := NewLinkedVersion("system", test.goroot)
+			if test.wantErr {
+				if err == nil {
+					t.Fatalf("NewLinkedVersion(%s) = %s, want error", test.goroot, linked.LinkPath)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewLinkedVersion(%s) = %v", test.goroot, err)
+			}
+			if linked.LinkPath != external || goVersion != "go1.24.2" {
+				t.Errorf("NewLinkedVersion(%s) = %s (%s), want %s (go1.24.2)", test.goroot, linked.LinkPath, goVersion, external)
+			}
+		})
+	}
+}
+
+func TestNewLinkedVersionNames(t *testing.T) {
+	goroot := filepath.Join(t.TempDir(), "go")
+	writeFakeGoRoot(t, goroot, "go1.22.5")
+
+	tests := []struct {
+		name    string
+		wantErr bool
+	}{
+		{name: "system-1.22"},
+		{name: "my go"},
+		{name: "", wantErr: true},
+		{name: "  ", wantErr: true},
+		{name: "sys/tem", wantErr: true},
+		{name: `sys\tem`, wantErr: true},
+		{name: "sys\ttem", wantErr: true},
+		{name: "system\n", wantErr: true},
+		{name: "1.22", wantErr: true},
+		{name: "go1.22.5", wantErr: true},
+		{name: "go1.23rc1", wantErr: true},
+	}
+
+	for _, test := range tests {
+		linked, _, err := NewLinkedVersion(test.name, goroot)
+		if test.wantErr {
+			if err == nil {
+				t.Errorf("NewLinkedVersion(%q) = %s, want error", test.name, linked.Version)
+			}
+			continue
+		}
+		if err != nil || linked.Version != test.name {
+			t.Errorf("NewLinkedVersion(%q) = %v, %v", test.name, linked, err)
+		}
+	}
+}
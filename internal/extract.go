@@ -0,0 +1,408 @@
+package internal
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	progressbar "github.com/schollz/progressbar/v3"
+)
+
+// Extracts a golang release archive (.tar.gz or .zip) into destDir,
+// the same way `tar -C <destDir> -xzf <archive>` would.
+// Entries are first written into a temporary directory inside destDir
+// and moved into place only once the whole archive was extracted, so
+// a failed extraction never leaves a half written toolchain behind.
+func ExtractArchive(archivePath string, destDir string) error {
+	if err := os.MkdirAll(destDir, 0755); err != nil {
+		return fmt.Errorf("Extract Error: failed to create destination %s: %w", destDir, err)
+	}
+
+	tmpDir, err := os.MkdirTemp(destDir, ".gvm-extract-")
+	if err != nil {
+		return fmt.Errorf("Extract Error: failed to create temp directory: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	if strings.HasSuffix(archivePath, ".zip") {
+		err = extractZip(archivePath, tmpDir)
+	} else {
+		err = extractTarGz(archivePath, tmpDir)
+	}
+	if err != nil {
+		return err
+	}
+
+	entries, err := os.ReadDir(tmpDir)
+	if err != nil {
+		return fmt.Errorf("Extract Error: %w", err)
+	}
+
+	for _, entry := range entries {
+		target := filepath.Join(destDir, entry.Name())
+		if _, err := os.Lstat(target); err == nil {
+			return fmt.Errorf("Extract Error: %s already exists", target)
+		}
+		if err := os.Rename(filepath.Join(tmpDir, entry.Name()), target); err != nil {
+			return fmt.Errorf("Extract Error: failed to move %s into place: %w", entry.Name(), err)
+		}
+	}
+
+	return nil
+}
+
+// Resolves an archive entry name against root and rejects any entry
+// which would end up outside of it (e.g. "../../etc/passwd"). Entries
+// are never written through a symlink extracted earlier, a chain of
+// symlinks could otherwise point anywhere.
+func safeJoin(root string, name string) (string, error) {
+	if filepath.IsAbs(name) || strings.HasPrefix(name, "/") {
+		return "", fmt.Errorf("Extract Error: illegal absolute path in archive: %s", name)
+	}
+
+	target := filepath.Join(root, filepath.FromSlash(name))
+	if !isWithinRoot(root, target) {
+		return "", fmt.Errorf("Extract Error: illegal path traversal in archive: %s", name)
+	}
+
+	rel, err := filepath.Rel(root, target)
+	if err != nil {
+		return "", fmt.Errorf("Extract Error: %w", err)
+	}
+
+	current := root
+	for _, component := range strings.Split(rel, string(os.PathSeparator)) {
+		if component == "." {
+			continue
+		}
+		current = filepath.Join(current, component)
+
+		info, err := os.Lstat(current)
+		if err != nil {
+			// nothing below a missing component exists either
+			break
+		}
+		if info.Mode()&os.ModeSymlink != 0 {
+			return "", fmt.Errorf("Extract Error: refusing to write %s through symlink %s", name, current)
+		}
+	}
+
+	return target, nil
+}
+
+func isWithinRoot(root string, path string) bool {
+	return path == root || strings.HasPrefix(path, root+string(os.PathSeparator))
+}
+
+// Rejects symlinks whose target resolves outside of root. The target is
+// resolved component by component following the symlinks extracted so
+// far, as the kernel would, so "l1/../.." is checked against wherever
+// l1 really points to rather than lexically.
+func validateSymlink(root string, linkPath string, linkTarget string) error {
+	if filepath.IsAbs(linkTarget) || strings.HasPrefix(linkTarget, "/") {
+		return fmt.Errorf("Extract Error: illegal absolute symlink %s -> %s", linkPath, linkTarget)
+	}
+
+	if _, err := resolveInRoot(root, filepath.Dir(linkPath), linkTarget, 0); err != nil {
+		return fmt.Errorf("Extract Error: symlink escapes archive root %s -> %s", linkPath, linkTarget)
+	}
+
+	return nil
+}
+
+// Maximum number of symlinks followed while resolving a link target,
+// like the kernel's ELOOP limit.
+const maxSymlinkDepth = 40
+
+// Walks the slash separated path starting in dir (a real directory
+// inside root) and returns where it ends up. Fails as soon as the walk
+// leaves root.
+func resolveInRoot(root string, dir string, path string, depth int) (string, error) {
+	if depth > maxSymlinkDepth {
+		return "", fmt.Errorf("too many levels of symbolic links")
+	}
+
+	current := dir
+	for _, component := range strings.Split(filepath.ToSlash(path), "/") {
+		switch component {
+		case "", ".":
+			continue
+		case "..":
+			if current == root {
+				return "", fmt.Errorf("path leaves root")
+			}
+			current = filepath.Dir(current)
+			continue
+		}
+
+		next := filepath.Join(current, component)
+		info, err := os.Lstat(next)
+		if err != nil || info.Mode()&os.ModeSymlink == 0 {
+			current = next
+			continue
+		}
+
+		target, err := os.Readlink(next)
+		if err != nil {
+			return "", err
+		}
+		if filepath.IsAbs(target) || strings.HasPrefix(target, "/") {
+			return "", fmt.Errorf("path leaves root")
+		}
+		if current, err = resolveInRoot(root, current, target, depth+1); err != nil {
+			return "", err
+		}
+	}
+
+	if !isWithinRoot(root, current) {
+		return "", fmt.Errorf("path leaves root")
+	}
+	return current, nil
+}
+
+func writeExtractedFile(target string, src io.Reader, mode os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return err
+	}
+
+	out, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode.Perm())
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(out, src); err != nil {
+		out.Close()
+		return err
+	}
+
+	if err := out.Close(); err != nil {
+		return err
+	}
+
+	// OpenFile is subject to umask, chmod restores the archived mode.
+	return os.Chmod(target, mode.Perm())
+}
+
+func extractTarGz(archivePath string, root string) error {
+	file, err := os.Open(archivePath)
+	if err != nil {
+		return fmt.Errorf("Extract Error: %w", err)
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return fmt.Errorf("Extract Error: %w", err)
+	}
+
+	progress := progressbar.DefaultBytes(info.Size(), "extracting")
+	defer progress.Close()
+
+	reader := progressbar.NewReader(file, progress)
+	gz, err := gzip.NewReader(&reader)
+	if err != nil {
+		return fmt.Errorf("Extract Error: %s is not a gzip archive: %w", archivePath, err)
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("Extract Error: corrupt tar archive: %w", err)
+		}
+
+		target, err := safeJoin(root, header.Name)
+		if err != nil {
+			return err
+		}
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, os.FileMode(header.Mode).Perm()|0700); err != nil {
+				return fmt.Errorf("Extract Error: %w", err)
+			}
+		case tar.TypeReg:
+			if err := writeExtractedFile(target, tr, os.FileMode(header.Mode)); err != nil {
+				return fmt.Errorf("Extract Error: failed to write %s: %w", header.Name, err)
+			}
+		case tar.TypeSymlink:
+			if err := validateSymlink(root, target, header.Linkname); err != nil {
+				return err
+			}
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return fmt.Errorf("Extract Error: %w", err)
+			}
+			if err := os.Symlink(header.Linkname, target); err != nil {
+				return fmt.Errorf("Extract Error: %w", err)
+			}
+		case tar.TypeLink:
+			linkSource, err := safeJoin(root, header.Linkname)
+			if err != nil {
+				return err
+			}
+			if err := os.Link(linkSource, target); err != nil {
+				return fmt.Errorf("Extract Error: %w", err)
+			}
+		default:
+			// pax headers, device nodes and fifos never appear in go releases
+			continue
+		}
+	}
+
+	return nil
+}
+
+func extractZip(archivePath string, root string) error {
+	zr, err := zip.OpenReader(archivePath)
+	if err != nil {
+		return fmt.Errorf("Extract Error: %s is not a zip archive: %w", archivePath, err)
+	}
+	defer zr.Close()
+
+	var totalSize int64
+	for _, f := range zr.File {
+		totalSize += int64(f.UncompressedSize64)
+	}
+
+	progress := progressbar.DefaultBytes(totalSize, "extracting")
+	defer progress.Close()
+
+	for _, f := range zr.File {
+		target, err := safeJoin(root, f.Name)
+		if err != nil {
+			return err
+		}
+
+		mode := f.Mode()
+		switch {
+		case mode.IsDir():
+			if err := os.MkdirAll(target, mode.Perm()|0700); err != nil {
+				return fmt.Errorf("Extract Error: %w", err)
+			}
+		case mode&os.ModeSymlink != 0:
+			rc, err := f.Open()
+			if err != nil {
+				return fmt.Errorf("Extract Error: %w", err)
+			}
+			linkTarget, err := io.ReadAll(rc)
+			rc.Close()
+			if err != nil {
+				return fmt.Errorf("Extract Error: %w", err)
+			}
+			if err := validateSymlink(root, target, string(linkTarget)); err != nil {
+				return err
+			}
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return fmt.Errorf("Extract Error: %w", err)
+			}
+			if err := os.Symlink(string(linkTarget), target); err != nil {
+				return fmt.Errorf("Extract Error: %w", err)
+			}
+		default:
+			rc, err := f.Open()
+			if err != nil {
+				return fmt.Errorf("Extract Error: %w", err)
+			}
+			perm := mode.Perm()
+			if perm == 0 {
+				perm = 0644
+			}
+			err = writeExtractedFile(target, io.TeeReader(rc, progress), perm)
+			rc.Close()
+			if err != nil {
+				return fmt.Errorf("Extract Error: failed to write %s: %w", f.Name, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Packs the directory srcDir into a .tar.gz archive at archivePath with
+// every entry placed under prefix (e.g. "go"), mirroring the layout of
+// official golang release archives.
+func CreateTarGz(srcDir string, prefix string, archivePath string) error {
+	tmp, err := os.CreateTemp(filepath.Dir(archivePath), ".gvm-archive-")
+	if err != nil {
+		return fmt.Errorf("Archive Error: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	gz := gzip.NewWriter(tmp)
+	tw := tar.NewWriter(gz)
+
+	walkErr := filepath.Walk(srcDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(srcDir, path)
+		if err != nil {
+			return err
+		}
+
+		var linkTarget string
+		if info.Mode()&os.ModeSymlink != 0 {
+			if linkTarget, err = os.Readlink(path); err != nil {
+				return err
+			}
+		}
+
+		header, err := tar.FileInfoHeader(info, linkTarget)
+		if err != nil {
+			return err
+		}
+		header.Name = filepath.ToSlash(filepath.Join(prefix, rel))
+		if info.IsDir() {
+			header.Name += "/"
+		}
+
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		_, err = io.Copy(tw, file)
+		return err
+	})
+
+	if walkErr != nil {
+		tmp.Close()
+		return fmt.Errorf("Archive Error: failed to pack %s: %w", srcDir, walkErr)
+	}
+
+	if err := tw.Close(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("Archive Error: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("Archive Error: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("Archive Error: %w", err)
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return fmt.Errorf("Archive Error: %w", err)
+	}
+
+	return os.Rename(tmp.Name(), archivePath)
+}
@@ -0,0 +1,214 @@
+package internal
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// Entry of a synthetic test archive.
+type archiveEntry struct {
+	name string
+	body string
+	mode os.FileMode
+	// symlink target, the entry is a symlink when set
+	link string
+	dir  bool
+}
+
+func writeTestTarGz(t *testing.T, path string, entries []archiveEntry) {
+	t.Helper()
+
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	gz := gzip.NewWriter(file)
+	tw := tar.NewWriter(gz)
+	for _, entry := range entries {
+		header := &tar.Header{Name: entry.name, Mode: int64(entry.mode.Perm()), Typeflag: tar.TypeReg, Size: int64(len(entry.body))}
+		switch {
+		case entry.dir:
+			header.Typeflag, header.Size = tar.TypeDir, 0
+		case entry.link != "":
+			header.Typeflag, header.Linkname, header.Size = tar.TypeSymlink, entry.link, 0
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			t.Fatal(err)
+		}
+		if header.Typeflag == tar.TypeReg {
+			if _, err := tw.Write([]byte(entry.body)); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func writeTestZip(t *testing.T, path string, entries []archiveEntry) {
+	t.Helper()
+
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	zw := zip.NewWriter(file)
+	for _, entry := range entries {
+		header := &zip.FileHeader{Name: entry.name, Method: zip.Deflate}
+		body := entry.body
+		switch {
+		case entry.dir:
+			header.SetMode(os.ModeDir | entry.mode.Perm())
+		case entry.link != "":
+			header.SetMode(os.ModeSymlink | 0777)
+			body = entry.link
+		default:
+			header.SetMode(entry.mode.Perm())
+		}
+		w, err := zw.CreateHeader(header)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(body)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestExtractArchive(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries []archiveEntry
+		wantErr bool
+		// files expected below the destination with their mode
+		wantFiles map[string]os.FileMode
+	}{
+		{
+			name: "release layout keeps file modes",
+			entries: []archiveEntry{
+				{name: "go/", dir: true, mode: 0755},
+				{name: "go/VERSION", body: "go1.25.5\n", mode: 0644},
+				{name: "go/bin/go", body: "#!/bin/sh\n", mode: 0755},
+				{name: "go/pkg/tool/readonly", body: "x", mode: 0444},
+				{name: "go/misc/link", link: "../VERSION"},
+			},
+			wantFiles: map[string]os.FileMode{
+				"go/VERSION":           0644,
+				"go/bin/go":            0755,
+				"go/pkg/tool/readonly": 0444,
+			},
+		},
+		{
+			name:    "parent directory name",
+			entries: []archiveEntry{{name: "../PWNED", body: "x", mode: 0644}},
+			wantErr: true,
+		},
+		{
+			name:    "nested parent directory name",
+			entries: []archiveEntry{{name: "go/../../PWNED", body: "x", mode: 0644}},
+			wantErr: true,
+		},
+		{
+			name:    "absolute name",
+			entries: []archiveEntry{{name: "/tmp/PWNED", body: "x", mode: 0644}},
+			wantErr: true,
+		},
+		{
+			name:    "absolute symlink",
+			entries: []archiveEntry{{name: "go/etc", link: "/etc"}},
+			wantErr: true,
+		},
+		{
+			name:    "symlink out of root",
+			entries: []archiveEntry{{name: "go/up", link: "../.."}},
+			wantErr: true,
+		},
+		{
+			name: "chained symlinks escaping root",
+			entries: []archiveEntry{
+				{name: "go/a/", dir: true, mode: 0755},
+				{name: "go/a/l1", link: "."},
+				{name: "go/a/l2", link: "l1/../../.."},
+				{name: "go/a/l2/PWNED", body: "x", mode: 0644},
+			},
+			wantErr: true,
+		},
+		{
+			name: "file written through symlink inside root",
+			entries: []archiveEntry{
+				{name: "go/a/", dir: true, mode: 0755},
+				{name: "go/l1", link: "a"},
+				{name: "go/l1/file", body: "x", mode: 0644},
+			},
+			wantErr: true,
+		},
+		{
+			name: "file replacing a symlink",
+			entries: []archiveEntry{
+				{name: "go/VERSION", link: "../x"},
+				{name: "go/VERSION", body: "x", mode: 0644},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, format := range []string{".tar.gz", ".zip"} {
+		for _, test := range tests {
+			t.Run(format+"/"+test.name, func(t *testing.T) {
+				dir := t.TempDir()
+				archivePath := filepath.Join(dir, "archive"+format)
+				if format == ".zip" {
+					writeTestZip(t, archivePath, test.entries)
+				} else {
+					writeTestTarGz(t, archivePath, test.entries)
+				}
+
+				// nest the destination so escapes land in a checked directory
+				destDir := filepath.Join(dir, "outer", "dest")
+				err := ExtractArchive(archivePath, destDir)
+				if test.wantErr {
+					if err == nil {
+						t.Fatalf("ExtractArchive() succeeded, want error")
+					}
+				} else if err != nil {
+					t.Fatalf("ExtractArchive() = %v", err)
+				}
+
+				for _, escaped := range []string{
+					filepath.Join(dir, "PWNED"),
+					filepath.Join(dir, "outer", "PWNED"),
+					filepath.Join(dir, "outer", "dest", "PWNED"),
+				} {
+					if _, err := os.Lstat(escaped); err == nil {
+						t.Fatalf("%s was written outside of the archive root", escaped)
+					}
+				}
+
+				for name, mode := range test.wantFiles {
+					info, err := os.Stat(filepath.Join(destDir, filepath.FromSlash(name)))
+					if err != nil {
+						t.Fatalf("%s not extracted: %v", name, err)
+					}
+					if info.Mode().Perm() != mode {
+						t.Errorf("%s has mode %v, want %v", name, info.Mode().Perm(), mode)
+					}
+				}
+			})
+		}
+	}
+}
@@ -5,7 +5,6 @@ import (
 	"io"
 	"net/http"
 	"os"
-	"path/filepath"
 	"strings"
 
 	"github.com/PuerkitoBio/goquery"
@@ -18,10 +17,91 @@ import (
 type RemoteVersion struct {
 	Version      string `json:"version"`
 	DownloadLink string `json:"download_link"`
+	// sha256 of the archive, when published by the version source
+	SHA256 string `json:"sha256,omitempty"`
 }
 
+// File name of the archive in the per user view, e.g. "go1.25.5.tar.gz".
+func (rv *RemoteVersion) ArchiveFileName() string {
+	ext := ".tar.gz"
+	if strings.HasSuffix(rv.DownloadLink, ".zip") {
+		ext = ".zip"
+	}
+	return rv.Version + ext
+}
+
+// Downloads the archive into the shared download cache, unless another
+// user already did, and links it into the per user view.
+// Returns the path in the per user view.
 func (rv *RemoteVersion) Download() (*string, error) {
-	resp, err := http.Get(rv.DownloadLink)
+	return rv.download(true, nil)
+}
+
+// Downloads like Download and checks the archive contains the version it
+// claims to and its signature (see VerifySignature) before it's added to
+// the shared cache, so archives failing them are never served to other
+// users. Returns the path in the per user view and whether a signature
+// was verified.
+func (c *Config) DownloadVerified(rv *RemoteVersion, showProgress bool, requireSignature bool) (string, bool, error) {
+	signed := false
+	path, err := rv.download(showProgress, func(archivePath string) error {
+		if err := VerifyArchiveVersion(archivePath, rv.Version); err != nil {
+			return err
+		}
+
+		var err error
+		signed, err = c.VerifySignature(archivePath, RemoteSignatureLoader(rv.DownloadLink), requireSignature)
+		return err
+	})
+	if err != nil {
+		return "", false, err
+	}
+
+	return *path, signed, nil
+}
+
+// Downloads like Download. Parallel downloads disable the progress
+// output, which would otherwise garble the terminal. verify, when set,
+// runs on a fresh download before it enters the cache and on a cached
+// archive once it's linked into the per user view.
+func (rv *RemoteVersion) download(showProgress bool, verify func(archivePath string) error) (*string, error) {
+	var cached *CacheEntry
+	var err error
+
+	if rv.SHA256 != "" {
+		cached, err = LookupCacheBySHA256(rv.SHA256)
+	} else {
+		cached, err = LookupCacheByURL(rv.DownloadLink)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	wasCached := cached != nil
+	if !wasCached {
+		if cached, err = rv.downloadIntoCache(showProgress, verify); err != nil {
+			return nil, err
+		}
+	} else if showProgress {
+		color.Cyan(fmt.Sprintf("Using cached archive %s", ShortChecksum(cached.SHA256)))
+	}
+
+	filePath, err := LinkCachedArchive(cached, rv.ArchiveFileName())
+	if err != nil {
+		return nil, err
+	}
+
+	if wasCached && verify != nil {
+		if err := verify(filePath); err != nil {
+			return nil, err
+		}
+	}
+
+	return &filePath, nil
+}
+
+func (rv *RemoteVersion) downloadIntoCache(showProgress bool, verify func(archivePath string) error) (*CacheEntry, error) {
+	resp, err := HTTPClient().Get(rv.DownloadLink)
 	if err != nil {
 		return nil, fmt.Errorf("download error (%s): %w", rv.Version, err)
 	}
@@ -31,27 +111,30 @@ func (rv *RemoteVersion) Download() (*string, error) {
 		return nil, fmt.Errorf("download failed (%s): %s", rv.Version, resp.Status)
 	}
 
-	downloadDirPath, err := GoDownloadDir()
-	if err != nil {
-		return nil, err
+	var progress io.Writer = io.Discard
+	if showProgress {
+		progress = progressbar.DefaultBytes(resp.ContentLength, "downloading")
 	}
 
-	filePath := filepath.Join(*downloadDirPath, fmt.Sprintf("%s.tar.gz", rv.Version))
-
-	out, err := os.Create(filePath)
+	tmpPath, err := downloadToCache(resp.Body, rv.ArchiveFileName(), progress)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("download error (%s): %w", rv.Version, err)
 	}
-	defer out.Close()
+	defer os.Remove(tmpPath)
 
-	totalSize := resp.ContentLength
-	progress := progressbar.DefaultBytes(totalSize, "downloading")
+	if rv.SHA256 != "" {
+		if err := VerifyFileChecksum(tmpPath, rv.SHA256); err != nil {
+			return nil, err
+		}
+	}
 
-	if _, err := io.Copy(io.MultiWriter(out, progress), resp.Body); err != nil {
-		color.Red(err.Error())
+	if verify != nil {
+		if err := verify(tmpPath); err != nil {
+			return nil, err
+		}
 	}
 
-	return &filePath, nil
+	return AddFileToCache(tmpPath, rv.ArchiveFileName(), rv.DownloadLink, true)
 }
 
 // Golang release url
@@ -62,7 +145,7 @@ const GO_GITHUB_RELEASE_URL = "https://github.com/golang/go/tags"
 // Then it uses goquery to parse HTML and fetch 10 most recent golang
 // versions
 func FetchGoVersionsFromGoGithubRelease() ([]RemoteVersion, error) {
-	response, err := http.Get(GO_GITHUB_RELEASE_URL)
+	response, err := HTTPClient().Get(GO_GITHUB_RELEASE_URL)
 	if err != nil {
 		return nil, err
 	}
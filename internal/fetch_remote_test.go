@@ -0,0 +1,68 @@
+package internal
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestDownloadVerifiedRejectsBeforeCaching(t *testing.T) {
+	if _, err := CacheDir(); err != nil {
+		t.Skipf("shared cache unavailable: %v", err)
+	}
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_DATA_HOME", "")
+
+	tests := []struct {
+		name             string
+		archiveVersion   string
+		requireSignature bool
+	}{
+		{name: "unsigned archive with signatures required", archiveVersion: "go1.25.5", requireSignature: true},
+		{name: "archive of another version", archiveVersion: "go1.25.4"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// unique content so the archive can't already be cached
+			archivePath := filepath.Join(t.TempDir(), "go.tar.gz")
+			writeTestTarGz(t, archivePath, []archiveEntry{
+				{name: archiveVersionFile, body: test.archiveVersion + "\n", mode: 0644},
+				{name: "go/nonce", body: strconv.FormatInt(time.Now().UnixNano(), 10), mode: 0644},
+			})
+			sha, err := FileSHA256(archivePath)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/go1.25.5.linux-amd64.tar.gz" {
+					http.NotFound(w, r)
+					return
+				}
+				http.ServeFile(w, r, archivePath)
+			}))
+			defer server.Close()
+
+			remote := &RemoteVersion{Version: "go1.25.5", DownloadLink: server.URL + "/go1.25.5.linux-amd64.tar.gz", SHA256: sha}
+			config := &Config{}
+			if _, _, err := config.DownloadVerified(remote, false, test.requireSignature); err == nil {
+				t.Fatal("DownloadVerified() succeeded, want error")
+			}
+
+			cached, err := LookupCacheBySHA256(sha)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if cached != nil {
+				os.Remove(cached.Path)
+				os.Remove(cached.Path + cacheMetaSuffix)
+				t.Fatalf("rejected archive was added to the cache as %s", cached.Path)
+			}
+		})
+	}
+}
@@ -4,35 +4,81 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
-	"path"
+	"path/filepath"
 	"strings"
 
 	"github.com/fatih/color"
 )
 
-// Fetches current golang version from CMD
-// It uses `go version` command.
+// Reported as the current version when no go installation is found.
+const NoGoVersion = "none"
+
+// Installation managed by `gvm use`, which is purged when switching.
+var globalGoRoot = filepath.Join("/usr/local", "go")
+
+// Fetches the version of the go active in the working directory, see
+// ResolveActiveVersion. Returns NoGoVersion when go isn't installed.
 func GetCurrentGolangVersion() (*string, error) {
-	res, err := exec.Command("go", "version").Output()
+	dir, err := os.Getwd()
+	if err != nil {
+		dir = "."
+	}
+
+	active, err := ResolveActiveVersion(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	version := NoGoVersion
+	if active != nil {
+		version = active.Version
+	}
+
+	return &version, nil
+}
+
+// Detects the version of the go installation at goroot from its VERSION
+// file. Only when the file is missing or holds no release version (e.g.
+// a devel build) `go version` is run.
+func DetectGoRootVersion(goroot string) (string, error) {
+	if data, err := os.ReadFile(filepath.Join(goroot, "VERSION")); err == nil {
+		version := strings.TrimSpace(strings.SplitN(string(data), "\n", 2)[0])
+		if strings.HasPrefix(version, "go") {
+			return version, nil
+		}
+	}
+
+	return ReadGoRootVersion(goroot)
+}
+
+// Finds the go binary in PATH, falling back to the global installation
+// of `gvm use` when PATH doesn't contain it (e.g. in a fresh shell).
+// Symlinks are resolved. Returns an empty string when go isn't installed.
+func findGoBinary() string {
+	goPath, err := exec.LookPath("go")
 	if err != nil {
-		fmt.Println(err)
-		return nil, fmt.Errorf("failed to fetch go version")
+		goPath = filepath.Join(globalGoRoot, "bin", "go")
+		if _, err := os.Stat(goPath); err != nil {
+			return ""
+		}
 	}
 
-	return &strings.Split(string(res), " ")[2], nil
+	if resolved, err := filepath.EvalSymlinks(goPath); err == nil {
+		goPath = resolved
+	}
+	return goPath
 }
 
 func PurgeCurrentGolangInstallation() {
-	pathToDelete := path.Join("/usr", "local", "go")
+	pathToDelete := globalGoRoot
 
 	if _, err := os.ReadDir(pathToDelete); os.IsNotExist(err) {
 		return
 	}
 
-	version, err := GetCurrentGolangVersion()
+	version, err := DetectGoRootVersion(pathToDelete)
 	if err != nil {
-		color.Red(err.Error())
-		os.Exit(1)
+		version = pathToDelete
 	}
 
 	if err := os.RemoveAll(pathToDelete); err != nil {
@@ -40,5 +86,5 @@ func PurgeCurrentGolangInstallation() {
 		os.Exit(1)
 	}
 
-	color.Green(fmt.Sprintf("Successfully removed current golang version %s", *version))
+	color.Green(fmt.Sprintf("Successfully removed current golang version %s", version))
 }
@@ -0,0 +1,62 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestDetectGoRootVersion(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake go installations are shell scripts")
+	}
+
+	tests := []struct {
+		name      string
+		version   string
+		noVersion bool
+		noBinary  bool
+		want      string
+		wantErr   bool
+	}{
+		{name: "release", version: "go1.25.5\ntime 2025-12-02T21:19:59Z\n", want: "go1.25.5"},
+		{name: "prerelease", version: "go1.26rc1\n", want: "go1.26rc1"},
+		{name: "surrounding whitespace", version: "  go1.24.2  \n", want: "go1.24.2"},
+		{name: "no trailing newline", version: "go1.22.0", want: "go1.22.0"},
+		{name: "devel build runs go version", version: "devel go1.26-1a2b3c4 Tue Dec 2 2025\n", want: "devel"},
+		{name: "empty file runs go version", version: "", want: "devel"},
+		{name: "missing file runs go version", noVersion: true, want: "devel"},
+		{name: "no VERSION and no go", noVersion: true, noBinary: true, wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			goroot := filepath.Join(t.TempDir(), "go")
+			if test.noBinary {
+				if err := os.MkdirAll(goroot, 0755); err != nil {
+					t.Fatal(err)
+				}
+			} else {
+				// bin/go reports "devel", telling apart when `go version` was run
+				writeFakeGoRoot(t, goroot, "devel")
+			}
+			if !test.noVersion {
+				if err := os.WriteFile(filepath.Join(goroot, "VERSION"), []byte(test.version), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			got, err := DetectGoRootVersion(goroot)
+			if test.wantErr {
+				if err == nil {
+					t.Fatalf("DetectGoRootVersion() = %s, want error", got)
+				}
+				return
+			}
+			if err != nil || got != test.want {
+				t.Errorf("DetectGoRootVersion() = %q, %v, want %q", got, err, test.want)
+			}
+		})
+	}
+}
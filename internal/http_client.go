@@ -0,0 +1,220 @@
+package internal
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/net/http/httpproxy"
+)
+
+// Defaults applied when the config leaves a timeout unset.
+const (
+	DefaultConnectTimeoutSeconds = 30
+	DefaultUserAgent             = AppName + "/" + AppVersion
+)
+
+// Network settings shared by every request gvm makes.
+type HTTPConfig struct {
+	// Proxy url used for http and https requests. When empty the
+	// HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables apply.
+	Proxy string `json:"proxy,omitempty"`
+	// Hosts bypassing Proxy, in NO_PROXY syntax. Defaults to $NO_PROXY.
+	NoProxy string `json:"no_proxy,omitempty"`
+	// PEM file with extra CA certificates trusted in addition to the
+	// system pool, e.g. the one of a TLS intercepting proxy.
+	CABundle string `json:"ca_bundle,omitempty"`
+	// Timeout for establishing a connection and receiving the response
+	// headers. Zero selects DefaultConnectTimeoutSeconds.
+	ConnectTimeoutSeconds int `json:"connect_timeout_seconds,omitempty"`
+	// Timeout for a whole request including the body. Zero disables it
+	// so slow links can still download large archives.
+	TimeoutSeconds int    `json:"timeout_seconds,omitempty"`
+	UserAgent      string `json:"user_agent,omitempty"`
+	// Extra headers, e.g. for authenticating against a corporate mirror.
+	Headers []HTTPHeader `json:"headers,omitempty"`
+}
+
+// Extra request header. When Host is set the header is only sent to
+// that host so credentials never leak to other servers. The config
+// scopes headers without a Host to the host of the version source.
+type HTTPHeader struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+	Host  string `json:"host,omitempty"`
+}
+
+var (
+	httpClientMu     sync.Mutex
+	httpClient       *http.Client
+	httpClientConfig HTTPConfig
+)
+
+// Returns the client used for every request gvm makes, built on first
+// use from the settings given to ConfigureHTTPClient. Invalid settings,
+// e.g. an unreadable CA bundle, fail every request of the client rather
+// than commands which never touch the network.
+func HTTPClient() *http.Client {
+	httpClientMu.Lock()
+	defer httpClientMu.Unlock()
+
+	if httpClient == nil {
+		client, err := NewHTTPClient(httpClientConfig)
+		if err != nil {
+			client = &http.Client{Transport: failingTransport{err: err}}
+		}
+		httpClient = client
+	}
+
+	return httpClient
+}
+
+// Sets the network settings the shared client is built from, replacing
+// a client built from earlier settings.
+func ConfigureHTTPClient(cfg HTTPConfig) {
+	httpClientMu.Lock()
+	defer httpClientMu.Unlock()
+
+	httpClientConfig = cfg
+	httpClient = nil
+}
+
+// Replaces the shared client, e.g. with a fake in tests.
+func SetHTTPClient(client *http.Client) {
+	httpClientMu.Lock()
+	defer httpClientMu.Unlock()
+
+	httpClient = client
+}
+
+// Builds an http client honoring the proxy, CA, timeout, user agent and
+// header settings.
+func NewHTTPClient(cfg HTTPConfig) (*http.Client, error) {
+	connectTimeout := time.Duration(cfg.ConnectTimeoutSeconds) * time.Second
+	if connectTimeout <= 0 {
+		connectTimeout = DefaultConnectTimeoutSeconds * time.Second
+	}
+
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if cfg.CABundle != "" {
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+
+		pem, err := os.ReadFile(cfg.CABundle)
+		if err != nil {
+			return nil, fmt.Errorf("Config Error: failed to read CA bundle: %w", err)
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("Config Error: no certificates found in CA bundle %s", cfg.CABundle)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	proxy, err := proxyFunc(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	transport := &http.Transport{
+		Proxy: proxy,
+		DialContext: (&net.Dialer{
+			Timeout:   connectTimeout,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		TLSClientConfig:       tlsConfig,
+		TLSHandshakeTimeout:   connectTimeout,
+		ResponseHeaderTimeout: connectTimeout,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          10,
+		IdleConnTimeout:       90 * time.Second,
+	}
+
+	userAgent := cfg.UserAgent
+	if userAgent == "" {
+		userAgent = DefaultUserAgent
+	}
+
+	return &http.Client{
+		Transport: &headerTransport{
+			base:      transport,
+			userAgent: userAgent,
+			headers:   cfg.Headers,
+		},
+		Timeout: time.Duration(cfg.TimeoutSeconds) * time.Second,
+	}, nil
+}
+
+func proxyFunc(cfg HTTPConfig) (func(*http.Request) (*url.URL, error), error) {
+	proxyConfig := httpproxy.FromEnvironment()
+
+	if cfg.Proxy != "" {
+		if _, err := url.Parse(cfg.Proxy); err != nil {
+			return nil, fmt.Errorf("Config Error: invalid proxy url %s: %w", cfg.Proxy, err)
+		}
+		proxyConfig.HTTPProxy = cfg.Proxy
+		proxyConfig.HTTPSProxy = cfg.Proxy
+	}
+	if cfg.NoProxy != "" {
+		proxyConfig.NoProxy = cfg.NoProxy
+	}
+
+	resolve := proxyConfig.ProxyFunc()
+	return func(req *http.Request) (*url.URL, error) {
+		return resolve(req.URL)
+	}, nil
+}
+
+// Adds the user agent and configured headers to every request,
+// including the ones issued while following redirects to the same host.
+type headerTransport struct {
+	base      http.RoundTripper
+	userAgent string
+	headers   []HTTPHeader
+}
+
+func (t *headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	if req.Header.Get("User-Agent") == "" {
+		req.Header.Set("User-Agent", t.userAgent)
+	}
+
+	// the client links redirected requests to the response redirecting them
+	origin := req
+	for origin.Response != nil && origin.Response.Request != nil {
+		origin = origin.Response.Request
+	}
+	sameHost := strings.EqualFold(origin.URL.Host, req.URL.Host)
+
+	for _, header := range t.headers {
+		switch {
+		case header.Host != "" && !strings.EqualFold(header.Host, req.URL.Hostname()):
+			continue
+		case header.Host == "" && !sameHost:
+			continue
+		}
+		req.Header.Set(header.Name, header.Value)
+	}
+
+	return t.base.RoundTrip(req)
+}
+
+// Fails every request with the error of building the client.
+type failingTransport struct {
+	err error
+}
+
+func (t failingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+	return nil, t.err
+}
@@ -0,0 +1,145 @@
+package internal
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestHeaderTransportRedirects(t *testing.T) {
+	var got http.Header
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Clone()
+	}))
+	defer other.Close()
+
+	var sameHost http.Header
+	origin := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/same":
+			sameHost = r.Header.Clone()
+		case "/other":
+			http.Redirect(w, r, other.URL+"/file", http.StatusFound)
+		default:
+			http.Redirect(w, r, "/same", http.StatusFound)
+		}
+	}))
+	defer origin.Close()
+
+	originURL, _ := url.Parse(origin.URL)
+	client, err := NewHTTPClient(HTTPConfig{Headers: []HTTPHeader{
+		{Name: "Authorization", Value: "Bearer secret"},
+		{Name: "X-Scoped", Value: "origin", Host: originURL.Hostname()},
+		{Name: "X-Elsewhere", Value: "elsewhere", Host: "example.invalid"},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := client.Get(origin.URL + "/start")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if sameHost.Get("Authorization") != "Bearer secret" || sameHost.Get("X-Scoped") != "origin" {
+		t.Errorf("headers missing after same host redirect: %v", sameHost)
+	}
+	if sameHost.Get("X-Elsewhere") != "" {
+		t.Errorf("header scoped to another host was sent: %v", sameHost)
+	}
+
+	resp, err = client.Get(origin.URL + "/other")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if got == nil {
+		t.Fatal("redirect target not requested")
+	}
+	if got.Get("Authorization") != "" {
+		t.Errorf("unscoped header leaked across hosts: %v", got)
+	}
+	if got.Get("User-Agent") != DefaultUserAgent {
+		t.Errorf("User-Agent = %q, want %q", got.Get("User-Agent"), DefaultUserAgent)
+	}
+}
+
+func TestConfigHTTPConfigScopesHeaders(t *testing.T) {
+	headers := []HTTPHeader{
+		{Name: "Authorization", Value: "Bearer secret"},
+		{Name: "X-Other", Value: "other", Host: "other.example"},
+	}
+
+	tests := []struct {
+		source    string
+		sourceURL string
+		want      []string
+	}{
+		{source: VersionSourceMirror, sourceURL: "https://mirror.example:8443/golang/", want: []string{"mirror.example", "other.example"}},
+		{source: VersionSourceMirror, sourceURL: "mirror", want: []string{"other.example"}},
+		{source: VersionSourceGoDev, want: []string{"go.dev", "other.example"}},
+		{source: VersionSourceFile, sourceURL: "/srv/releases.json", want: []string{"go.dev", "other.example"}},
+		{source: VersionSourceGitHub, want: []string{"github.com", "other.example"}},
+		{source: "", want: []string{"github.com", "other.example"}},
+	}
+
+	for _, test := range tests {
+		config := Config{
+			VersionSource:    test.source,
+			VersionSourceURL: test.sourceURL,
+			HTTP:             HTTPConfig{Headers: headers},
+		}
+
+		hosts := make([]string, 0)
+		for _, header := range config.httpConfig().Headers {
+			hosts = append(hosts, header.Host)
+		}
+		if !slices.Equal(hosts, test.want) {
+			t.Errorf("%s %s: httpConfig() header hosts = %v, want %v", test.source, test.sourceURL, hosts, test.want)
+		}
+	}
+
+	if headers[0].Host != "" {
+		t.Errorf("httpConfig() modified the config headers")
+	}
+}
+
+func TestHTTPClientReportsInvalidSettingsOnUse(t *testing.T) {
+	t.Cleanup(func() { ConfigureHTTPClient(HTTPConfig{}) })
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	// offline commands only load the config
+	t.Setenv("HOME", t.TempDir())
+	configPath, err := ConfigFilePath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Dir(configPath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	config := &Config{HTTP: HTTPConfig{CABundle: filepath.Join(t.TempDir(), "missing.pem")}}
+	if err := config.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadConfig(); err != nil {
+		t.Fatalf("LoadConfig() = %v", err)
+	}
+
+	if _, err := HTTPClient().Get(server.URL); err == nil || !strings.Contains(err.Error(), "CA bundle") {
+		t.Fatalf("Get() = %v, want CA bundle error", err)
+	}
+
+	ConfigureHTTPClient(HTTPConfig{})
+	response, err := HTTPClient().Get(server.URL)
+	if err != nil {
+		t.Fatalf("Get() = %v", err)
+	}
+	response.Body.Close()
+}
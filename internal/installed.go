@@ -0,0 +1,77 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Reports whether the files of a downloaded version are gone, e.g. the
+// tarball was deleted by hand or the linked installation was removed.
+func (dv *DownloadVersion) IsMissing() bool {
+	path := dv.TarPath
+	if dv.IsLink() {
+		path = dv.LinkPath
+	}
+
+	_, err := os.Stat(path)
+	return os.IsNotExist(err)
+}
+
+// Finds golang archives in the per user view and the download directory
+// which no downloaded version of the config refers to, e.g. left behind
+// by an interrupted download or a config reset. Register them with
+// `gvm install --file` or delete them.
+func (c *Config) FindOrphanArchives() ([]string, error) {
+	known := make(map[string]bool)
+	for _, downloadVersion := range c.DownloadedVersions {
+		if downloadVersion.TarPath != "" {
+			known[filepath.Clean(downloadVersion.TarPath)] = true
+		}
+	}
+
+	dirs := make([]string, 0, 2)
+	if userDir, err := UserVersionsDir(); err == nil {
+		dirs = append(dirs, userDir)
+	}
+	if c.DownloadPath != "" {
+		dirs = append(dirs, c.DownloadPath)
+	}
+
+	orphans := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, dir := range dirs {
+		entries, err := os.ReadDir(dir)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		for _, entry := range entries {
+			if !entry.Type().IsRegular() || !isGoArchiveName(entry.Name()) {
+				continue
+			}
+
+			path := filepath.Join(dir, entry.Name())
+			if !known[path] && !seen[path] {
+				seen[path] = true
+				orphans = append(orphans, path)
+			}
+		}
+	}
+
+	sort.Strings(orphans)
+	return orphans, nil
+}
+
+// Reports whether a file name looks like an archive stored by gvm, e.g.
+// go1.25.5.tar.gz or devel-1a2b3c4d.tar.gz.
+func isGoArchiveName(name string) bool {
+	if !strings.HasPrefix(name, "go") && !IsDevelVersion(name) {
+		return false
+	}
+	return strings.HasSuffix(name, ".tar.gz") || strings.HasSuffix(name, ".zip")
+}
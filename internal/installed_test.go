@@ -0,0 +1,90 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestFindOrphanArchives(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", t.TempDir())
+	userDir, err := UserVersionsDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	downloadDir := t.TempDir()
+
+	for _, path := range []string{
+		filepath.Join(userDir, "go1.25.5.linux-amd64.tar.gz"),
+		filepath.Join(userDir, "go1.24.2.linux-amd64.tar.gz"),
+		filepath.Join(userDir, "devel-1a2b3c4d.tar.gz"),
+		filepath.Join(userDir, "notes.txt"),
+		filepath.Join(userDir, "archive.tar.gz"),
+		filepath.Join(downloadDir, "go1.21.0.windows-amd64.zip"),
+		filepath.Join(downloadDir, "go1.22.0.linux-amd64.tar.gz"),
+	} {
+		if err := os.WriteFile(path, []byte("archive"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Mkdir(filepath.Join(userDir, "go1.23.0.tar.gz"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(filepath.Join(userDir, "go1.24.2.linux-amd64.tar.gz"), filepath.Join(userDir, "go1.20.0.tar.gz")); err != nil {
+		t.Fatal(err)
+	}
+
+	config := &Config{
+		DownloadPath: downloadDir,
+		DownloadedVersions: map[string]DownloadVersion{
+			"go1.25.5": {Version: "go1.25.5", TarPath: filepath.Join(userDir, "go1.25.5.linux-amd64.tar.gz")},
+			"go1.22.0": {Version: "go1.22.0", TarPath: filepath.Join(downloadDir, ".", "go1.22.0.linux-amd64.tar.gz")},
+			"system":   {Version: "system", LinkPath: "/usr/lib/go"},
+		},
+	}
+
+	orphans, err := config.FindOrphanArchives()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		filepath.Join(downloadDir, "go1.21.0.windows-amd64.zip"),
+		filepath.Join(userDir, "devel-1a2b3c4d.tar.gz"),
+		filepath.Join(userDir, "go1.24.2.linux-amd64.tar.gz"),
+	}
+	sort.Strings(want)
+	if !reflect.DeepEqual(orphans, want) {
+		t.Errorf("FindOrphanArchives() = %v, want %v", orphans, want)
+	}
+}
+
+func TestDownloadVersionIsMissing(t *testing.T) {
+	dir := t.TempDir()
+	tarPath := filepath.Join(dir, "go1.25.5.linux-amd64.tar.gz")
+	if err := os.WriteFile(tarPath, []byte("archive"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	goroot := filepath.Join(dir, "go")
+	if err := os.Mkdir(goroot, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		version DownloadVersion
+		want    bool
+	}{
+		{name: "tarball", version: DownloadVersion{Version: "go1.25.5", TarPath: tarPath}},
+		{name: "deleted tarball", version: DownloadVersion{Version: "go1.24.2", TarPath: filepath.Join(dir, "go1.24.2.tar.gz")}, want: true},
+		{name: "link", version: DownloadVersion{Version: "system", LinkPath: goroot}},
+		{name: "removed link target", version: DownloadVersion{Version: "system", LinkPath: filepath.Join(dir, "old-go"), TarPath: tarPath}, want: true},
+	}
+
+	for _, test := range tests {
+		if got := test.version.IsMissing(); got != test.want {
+			t.Errorf("%s: IsMissing() = %v, want %v", test.name, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,347 @@
+package internal
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Path of the VERSION file inside every official golang release archive.
+const archiveVersionFile = "go/VERSION"
+
+// Directory holding the compiler and tools of a release archive, one
+// <goos>_<goarch> subdirectory for the platform the archive is built for.
+const archiveToolDir = "go/pkg/tool/"
+
+// Computes the hex encoded sha256 digest of the file at path.
+func FileSHA256(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// Compares the sha256 digest of the file at path against expected.
+// The comparison is case insensitive and tolerates a "sha256:" prefix.
+func VerifyFileChecksum(path string, expected string) error {
+	expected = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(expected), "sha256:"))
+
+	actual, err := FileSHA256(path)
+	if err != nil {
+		return fmt.Errorf("Checksum Error: failed to hash %s: %w", path, err)
+	}
+
+	if actual != expected {
+		return fmt.Errorf("Checksum Error: %s has sha256 %s, expected %s", path, actual, expected)
+	}
+
+	return nil
+}
+
+// Reads the golang version from the go/VERSION file bundled in a release
+// archive without extracting it. Returns the canonical release name, e.g.
+// "go1.25.5", and rejects anything else such as devel builds.
+func ReadArchiveVersion(archivePath string) (string, error) {
+	var content string
+	var err error
+
+	if strings.HasSuffix(archivePath, ".zip") {
+		content, err = readZipVersionFile(archivePath)
+	} else {
+		content, err = readTarGzVersionFile(archivePath)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	// The first line holds the version, the following ones build metadata.
+	line := strings.TrimSpace(strings.SplitN(content, "\n", 2)[0])
+	version, err := ParseGoVersion(line)
+	if err != nil || !strings.HasPrefix(line, "go") {
+		return "", fmt.Errorf("Archive Error: unexpected %s contents in %s: %q", archiveVersionFile, archivePath, line)
+	}
+
+	return version.String(), nil
+}
+
+func readTarGzVersionFile(archivePath string) (string, error) {
+	file, err := os.Open(archivePath)
+	if err != nil {
+		return "", fmt.Errorf("Archive Error: %w", err)
+	}
+	defer file.Close()
+
+	gz, err := gzip.NewReader(bufio.NewReader(file))
+	if err != nil {
+		return "", fmt.Errorf("Archive Error: %s is not a gzip archive: %w", archivePath, err)
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", fmt.Errorf("Archive Error: corrupt tar archive: %w", err)
+		}
+
+		if strings.TrimPrefix(header.Name, "./") == archiveVersionFile {
+			data, err := io.ReadAll(tr)
+			if err != nil {
+				return "", fmt.Errorf("Archive Error: %w", err)
+			}
+			return string(data), nil
+		}
+	}
+
+	return "", fmt.Errorf("Archive Error: %s not found in %s. Is it a golang release archive?", archiveVersionFile, archivePath)
+}
+
+func readZipVersionFile(archivePath string) (string, error) {
+	zr, err := zip.OpenReader(archivePath)
+	if err != nil {
+		return "", fmt.Errorf("Archive Error: %s is not a zip archive: %w", archivePath, err)
+	}
+	defer zr.Close()
+
+	for _, f := range zr.File {
+		if f.Name != archiveVersionFile {
+			continue
+		}
+
+		rc, err := f.Open()
+		if err != nil {
+			return "", fmt.Errorf("Archive Error: %w", err)
+		}
+		defer rc.Close()
+
+		data, err := io.ReadAll(rc)
+		if err != nil {
+			return "", fmt.Errorf("Archive Error: %w", err)
+		}
+		return string(data), nil
+	}
+
+	return "", fmt.Errorf("Archive Error: %s not found in %s. Is it a golang release archive?", archiveVersionFile, archivePath)
+}
+
+// Detects the "goos/goarch" platform a golang release archive is built for
+// from its go/pkg/tool/<goos>_<goarch> directory, falling back to the file
+// name of the archive, e.g. go1.25.5.linux-amd64.tar.gz. Returns an empty
+// platform when neither tells.
+func ReadArchivePlatform(archivePath string) (string, error) {
+	var platform string
+	var err error
+
+	if strings.HasSuffix(archivePath, ".zip") {
+		platform, err = readZipPlatform(archivePath)
+	} else {
+		platform, err = readTarGzPlatform(archivePath)
+	}
+	if err != nil {
+		return "", err
+	}
+	if platform != "" {
+		return platform, nil
+	}
+
+	return archiveNamePlatform(filepath.Base(archivePath)), nil
+}
+
+// Rejects an archive built for another platform than the running one.
+// Archives whose platform can't be detected are rejected as well.
+func checkArchivePlatform(archivePath string) error {
+	platform, err := ReadArchivePlatform(archivePath)
+	if err != nil {
+		return err
+	}
+
+	if platform == "" {
+		return fmt.Errorf("Archive Error: can't tell the platform %s is built for. Pass --force to import it anyway", archivePath)
+	}
+	if platform != CurrentPlatform() {
+		return fmt.Errorf("Archive Error: %s is built for %s, not %s. Pass --force to import it anyway", archivePath, platform, CurrentPlatform())
+	}
+
+	return nil
+}
+
+// Maps an archive entry below go/pkg/tool/ to the platform of its
+// <goos>_<goarch> directory.
+func toolDirPlatform(name string) string {
+	rest, ok := strings.CutPrefix(strings.TrimPrefix(name, "./"), archiveToolDir)
+	if !ok {
+		return ""
+	}
+
+	dir, _, _ := strings.Cut(rest, "/")
+	goos, goarch, ok := strings.Cut(dir, "_")
+	if !ok || goos == "" || goarch == "" {
+		return ""
+	}
+
+	return goos + "/" + goarch
+}
+
+// Parses the platform from the file name of an official release archive,
+// go<version>.<goos>-<goarch>.tar.gz or .zip.
+func archiveNamePlatform(name string) string {
+	name = strings.TrimSuffix(strings.TrimSuffix(name, ".tar.gz"), ".zip")
+
+	dot := strings.LastIndex(name, ".")
+	if dot < 0 {
+		return ""
+	}
+	goos, goarch, ok := strings.Cut(name[dot+1:], "-")
+	if !ok || goos == "" || goarch == "" {
+		return ""
+	}
+
+	// go.dev names the GOARCH=arm archives after the arm version they target
+	if goarch == "armv6l" {
+		goarch = "arm"
+	}
+
+	return goos + "/" + goarch
+}
+
+func readTarGzPlatform(archivePath string) (string, error) {
+	file, err := os.Open(archivePath)
+	if err != nil {
+		return "", fmt.Errorf("Archive Error: %w", err)
+	}
+	defer file.Close()
+
+	gz, err := gzip.NewReader(bufio.NewReader(file))
+	if err != nil {
+		return "", fmt.Errorf("Archive Error: %s is not a gzip archive: %w", archivePath, err)
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return "", nil
+		}
+		if err != nil {
+			return "", fmt.Errorf("Archive Error: corrupt tar archive: %w", err)
+		}
+
+		if platform := toolDirPlatform(header.Name); platform != "" {
+			return platform, nil
+		}
+	}
+}
+
+func readZipPlatform(archivePath string) (string, error) {
+	zr, err := zip.OpenReader(archivePath)
+	if err != nil {
+		return "", fmt.Errorf("Archive Error: %s is not a zip archive: %w", archivePath, err)
+	}
+	defer zr.Close()
+
+	for _, f := range zr.File {
+		if platform := toolDirPlatform(f.Name); platform != "" {
+			return platform, nil
+		}
+	}
+
+	return "", nil
+}
+
+// Copies a golang release archive obtained out of band into the gvm
+// download cache. The version is detected from the archive itself and
+// the checksum is verified when expectedChecksum is not empty. Unless
+// force is set archives built for another platform are rejected.
+// No network access is performed.
+func ImportLocalArchive(archivePath string, expectedChecksum string, force bool) (*DownloadVersion, error) {
+	if _, err := os.Stat(archivePath); err != nil {
+		return nil, fmt.Errorf("Archive Error: %w", err)
+	}
+
+	if expectedChecksum != "" {
+		if err := VerifyFileChecksum(archivePath, expectedChecksum); err != nil {
+			return nil, err
+		}
+	}
+
+	version, err := ReadArchiveVersion(archivePath)
+	if err != nil {
+		return nil, err
+	}
+
+	if !force {
+		if err := checkArchivePlatform(archivePath); err != nil {
+			return nil, err
+		}
+	}
+
+	ext := ".tar.gz"
+	if strings.HasSuffix(archivePath, ".zip") {
+		ext = ".zip"
+	}
+
+	entry, err := AddFileToCache(archivePath, version+ext, "", false)
+	if err != nil {
+		return nil, err
+	}
+
+	destPath, err := LinkCachedArchive(entry, version+ext)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DownloadVersion{
+		Version: version,
+		TarPath: destPath,
+		SHA256:  entry.SHA256,
+	}, nil
+}
+
+// Copies src to dst through a temporary file so dst is either the old
+// or the complete new content, never a partial copy.
+func copyFile(src string, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	tmp, err := os.CreateTemp(filepath.Dir(dst), ".gvm-copy-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, in); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), dst)
+}
@@ -0,0 +1,134 @@
+package internal
+
+import (
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestReadArchiveVersion(t *testing.T) {
+	tests := []struct {
+		content string
+		want    string
+		wantErr bool
+	}{
+		{content: "go1.25.5\ntime 2025-12-02T21:19:59Z\n", want: "go1.25.5"},
+		{content: "go1.21rc2\n", want: "go1.21rc2"},
+		{content: "  go1.22.0  \n", want: "go1.22.0"},
+		{content: "go1.25.5 X:nocoverageredesign\n", wantErr: true},
+		{content: "devel go1.26-1a2b3c4 Tue Dec 2 2025\n", wantErr: true},
+		{content: "1.25.5\n", wantErr: true},
+		{content: "go1.25.05\n", wantErr: true},
+		{content: "go../../etc\n", wantErr: true},
+		{content: "", wantErr: true},
+	}
+
+	for _, format := range []string{".tar.gz", ".zip"} {
+		for _, test := range tests {
+			archivePath := filepath.Join(t.TempDir(), "go"+format)
+			entries := []archiveEntry{{name: archiveVersionFile, body: test.content, mode: 0644}}
+			if format == ".zip" {
+				writeTestZip(t, archivePath, entries)
+			} else {
+				writeTestTarGz(t, archivePath, entries)
+			}
+
+			got, err := ReadArchiveVersion(archivePath)
+			if test.wantErr {
+				if err == nil {
+					t.Errorf("%s: ReadArchiveVersion(%q) = %s, want error", format, test.content, got)
+				}
+				continue
+			}
+			if err != nil || got != test.want {
+				t.Errorf("%s: ReadArchiveVersion(%q) = %q, %v, want %q", format, test.content, got, err, test.want)
+			}
+		}
+	}
+}
+
+func TestReadArchivePlatform(t *testing.T) {
+	current := runtime.GOOS + "_" + runtime.GOARCH
+
+	tests := []struct {
+		name     string
+		file     string
+		entries  []string
+		want     string
+		checkErr string
+	}{
+		{
+			name:    "tool directory",
+			file:    "go1.25.5.tar.gz",
+			entries: []string{archiveVersionFile, "go/pkg/tool/" + current + "/compile"},
+			want:    CurrentPlatform(),
+		},
+		{
+			name:    "tar entries with ./ prefix",
+			file:    "go1.25.5.tar.gz",
+			entries: []string{"./go/pkg/tool/" + current + "/compile"},
+			want:    CurrentPlatform(),
+		},
+		{
+			name:     "tool directory of another platform",
+			file:     "go1.25.5." + runtime.GOOS + "-" + runtime.GOARCH + ".tar.gz",
+			entries:  []string{"go/pkg/tool/plan9_386/compile"},
+			want:     "plan9/386",
+			checkErr: "is built for plan9/386",
+		},
+		{
+			name:    "file name",
+			file:    "go1.25.5." + runtime.GOOS + "-" + runtime.GOARCH + ".tar.gz",
+			entries: []string{archiveVersionFile},
+			want:    CurrentPlatform(),
+		},
+		{
+			name:     "armv6l file name",
+			file:     "go1.25.5.plan9-armv6l.tar.gz",
+			entries:  []string{archiveVersionFile},
+			want:     "plan9/arm",
+			checkErr: "is built for plan9/arm",
+		},
+		{
+			name:     "unknown platform",
+			file:     "go.tar.gz",
+			entries:  []string{archiveVersionFile, "go/pkg/tool/compile"},
+			checkErr: "can't tell the platform",
+		},
+	}
+
+	for _, format := range []string{".tar.gz", ".zip"} {
+		for _, test := range tests {
+			t.Run(test.name+format, func(t *testing.T) {
+				archivePath := filepath.Join(t.TempDir(), strings.TrimSuffix(test.file, ".tar.gz")+format)
+				entries := make([]archiveEntry, 0, len(test.entries))
+				for _, name := range test.entries {
+					if format == ".zip" {
+						name = strings.TrimPrefix(name, "./")
+					}
+					entries = append(entries, archiveEntry{name: name, body: "go1.25.5\n", mode: 0644})
+				}
+				if format == ".zip" {
+					writeTestZip(t, archivePath, entries)
+				} else {
+					writeTestTarGz(t, archivePath, entries)
+				}
+
+				got, err := ReadArchivePlatform(archivePath)
+				if err != nil || got != test.want {
+					t.Errorf("ReadArchivePlatform() = %q, %v, want %q", got, err, test.want)
+				}
+
+				err = checkArchivePlatform(archivePath)
+				if test.checkErr == "" {
+					if err != nil {
+						t.Errorf("checkArchivePlatform() = %v", err)
+					}
+				} else if err == nil || !strings.Contains(err.Error(), test.checkErr) {
+					t.Errorf("checkArchivePlatform() = %v, want error containing %q", err, test.checkErr)
+				}
+			})
+		}
+	}
+}
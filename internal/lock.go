@@ -0,0 +1,418 @@
+package internal
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Name of the lockfile written by `gvm lock` next to gvm.toml, or next
+// to the .go-version or go.mod file pinning the project version.
+const LockFileName = "gvm.lock"
+
+// Format version of the lockfile, bumped on incompatible changes.
+const lockFormatVersion = 1
+
+// Exact toolchains resolved from the project's version pins, with the
+// archive url and checksum of every platform the team uses.
+type Lockfile struct {
+	Path       string
+	Toolchains []LockedToolchain
+}
+
+// A toolchain resolved to an exact release.
+type LockedToolchain struct {
+	// Manifest toolchain name or the path of the .go-version or go.mod
+	// file for the project pin
+	Name string
+	// Version as written in the file the request came from
+	Requested string
+	// File the request came from, relative to the lockfile
+	Source string
+	// Exact release name, e.g. "go1.25.5"
+	Version   string
+	Artifacts map[string]LockedArtifact
+}
+
+// Release archive of a locked toolchain for one platform.
+type LockedArtifact struct {
+	URL    string
+	SHA256 string
+}
+
+// A toolchain to lock: a manifest toolchain or the project version pin.
+type LockRequest struct {
+	Name      string
+	Requested string
+	// File the request came from, relative to the lockfile
+	Source    string
+	Platforms []string
+	// Checksums pinned by the manifest by platform
+	SHA256 map[string]string
+}
+
+// Finds gvm.lock in dir or one of its parents.
+func FindLockfile(dir string) (string, bool) {
+	return findUp(dir, LockFileName)
+}
+
+// Collects the toolchains of the nearest gvm.toml and the version pinned
+// by the nearest .go-version or go.mod. platforms applies to requests
+// without platforms of their own and defaults to the manifest platforms
+// or the running platform. Returns the path the lockfile belongs at.
+func ProjectLockRequests(dir string, platforms []string) ([]LockRequest, string, error) {
+	var manifest *Manifest
+	if path, ok := FindManifest(dir); ok {
+		loaded, err := LoadManifest(path)
+		if err != nil {
+			return nil, "", err
+		}
+		manifest = loaded
+	}
+	pin := ResolveProjectVersionRequest(dir)
+
+	var lockDir string
+	switch {
+	case manifest != nil:
+		lockDir = filepath.Dir(manifest.Path)
+	case pin != nil:
+		lockDir = filepath.Dir(pin.Source)
+	default:
+		return nil, "", fmt.Errorf("Lock Error: no %s, %s or go.mod found in %s or its parents", ManifestFileName, GoVersionFile, dir)
+	}
+
+	if len(platforms) == 0 && manifest != nil {
+		platforms = manifest.Platforms
+	}
+	if len(platforms) == 0 {
+		platforms = []string{CurrentPlatform()}
+	}
+	for _, platform := range platforms {
+		if err := validatePlatform(platform); err != nil {
+			return nil, "", fmt.Errorf("Lock Error: %w", err)
+		}
+	}
+
+	requests := make([]LockRequest, 0)
+	if manifest != nil {
+		for _, toolchain := range manifest.Toolchains {
+			request := LockRequest{
+				Name:      toolchain.Name,
+				Requested: toolchain.Version,
+				Source:    relativeLockPath(lockDir, manifest.Path),
+				Platforms: toolchain.Platforms,
+				SHA256:    toolchain.SHA256,
+			}
+			if len(request.Platforms) == 0 {
+				request.Platforms = platforms
+			}
+			requests = append(requests, request)
+		}
+	}
+
+	if pin != nil {
+		source := relativeLockPath(lockDir, pin.Source)
+		if slices.ContainsFunc(requests, func(r LockRequest) bool { return r.Name == source }) {
+			return nil, "", fmt.Errorf("Lock Error: %s toolchain '%s' clashes with the project pin in %s", ManifestFileName, source, pin.Source)
+		}
+		requests = append(requests, LockRequest{
+			Name:      source,
+			Requested: pin.Version,
+			Source:    source,
+			Platforms: platforms,
+		})
+	}
+
+	return requests, filepath.Join(lockDir, LockFileName), nil
+}
+
+func relativeLockPath(lockDir string, path string) string {
+	relative, err := filepath.Rel(lockDir, path)
+	if err != nil {
+		return filepath.ToSlash(path)
+	}
+	return filepath.ToSlash(relative)
+}
+
+// Resolves every request to an exact release available on all of its
+// platforms using the configured version source.
+func (c *Config) ResolveLock(requests []LockRequest, path string) (*Lockfile, error) {
+	source, err := c.GetVersionSource()
+	if err != nil {
+		return nil, err
+	}
+
+	platformSource, ok := source.(PlatformVersionSource)
+	if !ok {
+		return nil, fmt.Errorf("Lock Error: version source %s publishes no checksums, configure the %s, %s or %s source", source.Name(), VersionSourceGoDev, VersionSourceFile, VersionSourceMirror)
+	}
+
+	platforms := make([]string, 0)
+	for _, request := range requests {
+		for _, platform := range request.Platforms {
+			if !slices.Contains(platforms, platform) {
+				platforms = append(platforms, platform)
+			}
+		}
+	}
+
+	versions, err := platformSource.FetchPlatformVersions(platforms)
+	if err != nil {
+		return nil, fmt.Errorf("Lock Error: failed to fetch versions from %s: %w", source.Name(), err)
+	}
+
+	lockfile := &Lockfile{Path: path}
+	for _, request := range requests {
+		// only releases published for every platform of the request
+		names := make([]string, 0)
+		for _, remoteVersion := range versions[request.Platforms[0]] {
+			onAll := true
+			for _, platform := range request.Platforms[1:] {
+				onAll = onAll && findRemoteVersion(versions[platform], remoteVersion.Version) != nil
+			}
+			if onAll {
+				names = append(names, remoteVersion.Version)
+			}
+		}
+
+		version, ok := SelectVersion(&VersionRequest{Version: request.Requested, Source: request.Source}, names)
+		if !ok {
+			return nil, fmt.Errorf("Lock Error: no release matching %s (%s) is available for %s", request.Requested, request.Source, strings.Join(request.Platforms, ", "))
+		}
+
+		locked := LockedToolchain{
+			Name:      request.Name,
+			Requested: request.Requested,
+			Source:    request.Source,
+			Version:   version,
+			Artifacts: make(map[string]LockedArtifact),
+		}
+		for _, platform := range request.Platforms {
+			remoteVersion := findRemoteVersion(versions[platform], version)
+			if remoteVersion.SHA256 == "" {
+				return nil, fmt.Errorf("Lock Error: %s publishes no sha256 for %s on %s", source.Name(), version, platform)
+			}
+			if pinned := request.SHA256[platform]; pinned != "" && !strings.EqualFold(pinned, remoteVersion.SHA256) {
+				return nil, fmt.Errorf("Checksum Error: %s pins sha256 %s for %s on %s but %s lists %s", request.Source, pinned, version, platform, source.Name(), remoteVersion.SHA256)
+			}
+			locked.Artifacts[platform] = LockedArtifact{URL: remoteVersion.DownloadLink, SHA256: remoteVersion.SHA256}
+		}
+
+		lockfile.Toolchains = append(lockfile.Toolchains, locked)
+	}
+
+	sort.Slice(lockfile.Toolchains, func(i, j int) bool {
+		return lockfile.Toolchains[i].Name < lockfile.Toolchains[j].Name
+	})
+
+	return lockfile, nil
+}
+
+// Reads a lockfile written by Lockfile.Save.
+func LoadLockfile(path string) (*Lockfile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("Lock Error: %w", err)
+	}
+
+	document, err := ParseTOML(data)
+	if err != nil {
+		return nil, fmt.Errorf("Lock Error: %s: %w", path, err)
+	}
+
+	if version, _ := document["lock_version"].(int64); version != lockFormatVersion {
+		return nil, fmt.Errorf("Lock Error: %s has unsupported lock_version %v, run 'gvm lock' to regenerate it", path, document["lock_version"])
+	}
+
+	lockfile := &Lockfile{Path: path}
+	toolchains, _ := document["toolchains"].(map[string]any)
+	for name, value := range toolchains {
+		table, _ := value.(map[string]any)
+		locked := LockedToolchain{Name: name, Artifacts: make(map[string]LockedArtifact)}
+		locked.Requested, _ = table["requested"].(string)
+		locked.Source, _ = table["source"].(string)
+		locked.Version, _ = table["version"].(string)
+		if locked.Version == "" {
+			return nil, fmt.Errorf("Lock Error: %s: toolchain '%s' has no version", path, name)
+		}
+
+		artifacts, _ := table["artifacts"].(map[string]any)
+		for platform, value := range artifacts {
+			artifact, _ := value.(map[string]any)
+			url, _ := artifact["url"].(string)
+			checksum, _ := artifact["sha256"].(string)
+			if url == "" || !isSHA256Hex(checksum) {
+				return nil, fmt.Errorf("Lock Error: %s: toolchain '%s' has an invalid artifact for %s", path, name, platform)
+			}
+			locked.Artifacts[platform] = LockedArtifact{URL: url, SHA256: strings.ToLower(checksum)}
+		}
+
+		lockfile.Toolchains = append(lockfile.Toolchains, locked)
+	}
+
+	sort.Slice(lockfile.Toolchains, func(i, j int) bool {
+		return lockfile.Toolchains[i].Name < lockfile.Toolchains[j].Name
+	})
+
+	return lockfile, nil
+}
+
+// Writes the lockfile with toolchains and platforms in a stable order,
+// so that regenerating an unchanged lock produces no diff.
+func (l *Lockfile) Save() error {
+	var builder strings.Builder
+	builder.WriteString("# Generated by 'gvm lock', do not edit by hand.\n")
+	fmt.Fprintf(&builder, "lock_version = %d\n", lockFormatVersion)
+
+	for _, toolchain := range l.Toolchains {
+		key := "toolchains." + tomlKey(toolchain.Name)
+		fmt.Fprintf(&builder, "\n[%s]\n", key)
+		fmt.Fprintf(&builder, "requested = %s\n", tomlQuote(toolchain.Requested))
+		fmt.Fprintf(&builder, "source = %s\n", tomlQuote(toolchain.Source))
+		fmt.Fprintf(&builder, "version = %s\n", tomlQuote(toolchain.Version))
+
+		for _, platform := range toolchain.Platforms() {
+			artifact := toolchain.Artifacts[platform]
+			fmt.Fprintf(&builder, "\n[%s.artifacts.%s]\n", key, tomlKey(platform))
+			fmt.Fprintf(&builder, "url = %s\n", tomlQuote(artifact.URL))
+			fmt.Fprintf(&builder, "sha256 = %s\n", tomlQuote(artifact.SHA256))
+		}
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(l.Path), ".gvm.lock-")
+	if err != nil {
+		return fmt.Errorf("Lock Error: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.WriteString(builder.String()); err != nil {
+		tmp.Close()
+		return fmt.Errorf("Lock Error: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("Lock Error: %w", err)
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return fmt.Errorf("Lock Error: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), l.Path); err != nil {
+		return fmt.Errorf("Lock Error: %w", err)
+	}
+	return nil
+}
+
+// Platforms the toolchain is locked for, sorted.
+func (t *LockedToolchain) Platforms() []string {
+	platforms := make([]string, 0, len(t.Artifacts))
+	for platform := range t.Artifacts {
+		platforms = append(platforms, platform)
+	}
+	sort.Strings(platforms)
+	return platforms
+}
+
+// Finds a locked toolchain by its name, the version it was requested as
+// or its version, selected like SelectVersion does so "1.25" finds the
+// newest locked patch. Returns nil when the lockfile doesn't contain it.
+func (l *Lockfile) FindVersion(version string) *LockedToolchain {
+	for _, toolchain := range l.Toolchains {
+		if toolchain.Name == version || toolchain.Requested == version {
+			return &toolchain
+		}
+	}
+
+	versions := make([]string, 0, len(l.Toolchains))
+	for _, toolchain := range l.Toolchains {
+		versions = append(versions, toolchain.Version)
+	}
+	selected, ok := SelectVersion(&VersionRequest{Version: version}, versions)
+	if !ok {
+		return nil
+	}
+	for _, toolchain := range l.Toolchains {
+		if toolchain.Version == selected {
+			return &toolchain
+		}
+	}
+	return nil
+}
+
+// Describes how the lockfile is out of date with the requests, e.g.
+// after .go-version was edited. Returns nil when it's up to date.
+func (l *Lockfile) Outdated(requests []LockRequest) []string {
+	problems := make([]string, 0)
+
+	for _, request := range requests {
+		index := slices.IndexFunc(l.Toolchains, func(t LockedToolchain) bool { return t.Name == request.Name })
+		if index < 0 {
+			problems = append(problems, fmt.Sprintf("%s (%s in %s) is not locked", request.Name, request.Requested, request.Source))
+			continue
+		}
+
+		locked := l.Toolchains[index]
+		if locked.Requested != request.Requested {
+			problems = append(problems, fmt.Sprintf("%s requests %s but %s was generated for %s", request.Source, request.Requested, LockFileName, locked.Requested))
+			continue
+		}
+
+		for _, platform := range request.Platforms {
+			artifact, ok := locked.Artifacts[platform]
+			switch {
+			case !ok:
+				problems = append(problems, fmt.Sprintf("%s is not locked for %s", request.Name, platform))
+			case request.SHA256[platform] != "" && !strings.EqualFold(request.SHA256[platform], artifact.SHA256):
+				problems = append(problems, fmt.Sprintf("%s pins a different sha256 for %s on %s than %s", request.Source, request.Name, platform, LockFileName))
+			}
+		}
+	}
+
+	for _, locked := range l.Toolchains {
+		if !slices.ContainsFunc(requests, func(r LockRequest) bool { return r.Name == locked.Name }) {
+			problems = append(problems, fmt.Sprintf("%s is locked but no longer requested", locked.Name))
+		}
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+	return problems
+}
+
+// Returns the locked archive of the toolchain for the running platform
+// after checking that the version source still serves exactly that
+// archive. Any difference, e.g. a mirror serving another file, fails.
+func (c *Config) FrozenRemoteVersion(locked *LockedToolchain) (*RemoteVersion, error) {
+	platform := CurrentPlatform()
+	artifact, ok := locked.Artifacts[platform]
+	if !ok {
+		return nil, fmt.Errorf("Frozen Error: %s has no artifact of %s for %s, run 'gvm lock --platform %s'", LockFileName, locked.Version, platform, platform)
+	}
+
+	remote := c.FindAvailableVersion(locked.Version)
+	if remote == nil {
+		index, err := c.fetchFullIndex()
+		if err != nil {
+			return nil, fmt.Errorf("Frozen Error: %s is not in the cached version list and the version source failed: %w", locked.Version, err)
+		}
+		remote = findRemoteVersion(index, locked.Version)
+	}
+
+	switch {
+	case remote == nil:
+		return nil, fmt.Errorf("Frozen Error: the version source doesn't serve %s for %s", locked.Version, platform)
+	case remote.DownloadLink != artifact.URL:
+		return nil, fmt.Errorf("Frozen Error: the version source serves %s from %s, %s expects %s", locked.Version, remote.DownloadLink, LockFileName, artifact.URL)
+	case remote.SHA256 != "" && !strings.EqualFold(remote.SHA256, artifact.SHA256):
+		return nil, fmt.Errorf("Frozen Error: the version source lists sha256 %s for %s, %s expects %s", remote.SHA256, locked.Version, LockFileName, artifact.SHA256)
+	}
+
+	return &RemoteVersion{
+		Version:      remote.Version,
+		DownloadLink: artifact.URL,
+		SHA256:       artifact.SHA256,
+	}, nil
+}
@@ -0,0 +1,128 @@
+package internal
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestResolveLock(t *testing.T) {
+	config := &Config{
+		VersionSource:    VersionSourceFile,
+		VersionSourceURL: filepath.Join("testdata", "releases.json"),
+	}
+
+	tests := []struct {
+		name      string
+		requested string
+		source    string
+		platforms []string
+		want      string
+		wantErr   bool
+	}{
+		{name: "minor-only pin locks newest patch", requested: "1.25", source: ".go-version", platforms: []string{"linux/amd64"}, want: "go1.25.5"},
+		{name: "go.mod directive locks newest patch", requested: "1.24", source: "go.mod", platforms: []string{"linux/amd64"}, want: "go1.24.2"},
+		{name: "exact pin", requested: "1.25.0", source: ".go-version", platforms: []string{"linux/amd64", "windows/amd64"}, want: "go1.25.0"},
+		{name: "exact pin missing on a platform", requested: "1.25.3", source: ".go-version", platforms: []string{"linux/amd64", "windows/amd64"}, wantErr: true},
+		{name: "minimum version from go.mod", requested: "1.25.1", source: "go.mod", platforms: []string{"darwin/arm64"}, want: "go1.25.5"},
+		{name: "prerelease", requested: "1.26rc1", source: ".go-version", platforms: []string{"linux/amd64"}, want: "go1.26rc1"},
+		{name: "unreleased", requested: "1.27", source: ".go-version", platforms: []string{"linux/amd64"}, wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			requests := []LockRequest{{Name: test.source, Requested: test.requested, Source: test.source, Platforms: test.platforms}}
+			lockfile, err := config.ResolveLock(requests, LockFileName)
+			if test.wantErr {
+				if err == nil {
+					t.Fatalf("ResolveLock(%s) locked %s, want error", test.requested, lockfile.Toolchains[0].Version)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ResolveLock(%s) = %v", test.requested, err)
+			}
+
+			locked := lockfile.Toolchains[0]
+			if locked.Version != test.want {
+				t.Errorf("ResolveLock(%s) locked %s, want %s", test.requested, locked.Version, test.want)
+			}
+			for _, platform := range test.platforms {
+				artifact := locked.Artifacts[platform]
+				if !strings.Contains(artifact.URL, test.want+".") || !isSHA256Hex(artifact.SHA256) {
+					t.Errorf("artifact for %s = %+v", platform, artifact)
+				}
+			}
+		})
+	}
+}
+
+func TestLockfileFindVersion(t *testing.T) {
+	lockfile := &Lockfile{Toolchains: []LockedToolchain{
+		{Name: "legacy", Requested: "1.25.0", Version: "go1.25.0"},
+		{Name: ".go-version", Requested: "1.25", Version: "go1.25.5"},
+		{Name: "tools", Requested: "1.24", Version: "go1.24.2"},
+	}}
+
+	tests := []struct {
+		version string
+		want    string
+	}{
+		{version: "1.25", want: ".go-version"},
+		{version: "go1.25", want: ".go-version"},
+		{version: "1.24", want: "tools"},
+		{version: "1.25.0", want: "legacy"},
+		{version: "go1.25.5", want: ".go-version"},
+		{version: "tools", want: "tools"},
+		{version: "1.23", want: ""},
+	}
+
+	for _, test := range tests {
+		got := ""
+		if toolchain := lockfile.FindVersion(test.version); toolchain != nil {
+			got = toolchain.Name
+		}
+		if got != test.want {
+			t.Errorf("FindVersion(%s) = %q, want %q", test.version, got, test.want)
+		}
+	}
+}
+
+func TestLockfileSaveLoad(t *testing.T) {
+	lockfile := &Lockfile{
+		Path: filepath.Join(t.TempDir(), LockFileName),
+		Toolchains: []LockedToolchain{
+			{
+				Name:      ".go-version",
+				Requested: "1.25",
+				Source:    ".go-version",
+				Version:   "go1.25.5",
+				Artifacts: map[string]LockedArtifact{
+					"linux/amd64":   {URL: "https://go.dev/dl/go1.25.5.linux-amd64.tar.gz", SHA256: strings.Repeat("a", 64)},
+					"windows/amd64": {URL: "https://go.dev/dl/go1.25.5.windows-amd64.zip", SHA256: strings.Repeat("b", 64)},
+				},
+			},
+			{
+				Name:      "tools \"legacy\"",
+				Requested: "1.24.2",
+				Source:    "gvm.toml",
+				Version:   "go1.24.2",
+				Artifacts: map[string]LockedArtifact{
+					"darwin/arm64": {URL: "https://go.dev/dl/go1.24.2.darwin-arm64.tar.gz", SHA256: strings.Repeat("c", 64)},
+				},
+			},
+		},
+	}
+	if err := lockfile.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadLockfile(lockfile.Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(loaded, lockfile) {
+		t.Errorf("LoadLockfile() = %+v, want %+v", loaded, lockfile)
+	}
+}
@@ -0,0 +1,416 @@
+package internal
+
+import (
+	"encoding/hex"
+	"fmt"
+	"os"
+	"runtime"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Name of the checked in toolchain manifest, looked up from the working
+// directory upwards like go.mod.
+const ManifestFileName = "gvm.toml"
+
+// Toolchains a project requires, read from gvm.toml:
+//
+//	platforms = ["linux/amd64", "darwin/arm64"]
+//
+//	[toolchains.main]
+//	version = "1.25.5"
+//
+//	[toolchains.legacy]
+//	version = "1.21.13"
+//	platforms = ["linux/amd64"]
+//	sha256 = { "linux/amd64" = "..." }
+type Manifest struct {
+	Path string
+	// Platforms ("goos/goarch") of toolchains without their own list,
+	// empty for every platform
+	Platforms  []string
+	Toolchains []ManifestToolchain
+}
+
+// A named toolchain of the manifest.
+type ManifestToolchain struct {
+	Name string
+	// Exact release name, e.g. "go1.25.5"
+	Version   string
+	Platforms []string
+	// Pinned archive checksums by platform
+	SHA256 map[string]string
+}
+
+// Kinds of differences between the manifest and the installed versions.
+const (
+	// Toolchain required on this platform but not installed
+	DriftMissing = "missing"
+	// Installed archive doesn't match the checksum pinned in the manifest
+	DriftChecksum = "checksum"
+	// Installed version not listed in the manifest
+	DriftExtra = "extra"
+)
+
+// A difference between the manifest and the installed versions.
+type ManifestDrift struct {
+	Kind string `json:"kind"`
+	// Manifest toolchain name, empty for extra versions
+	Toolchain string `json:"toolchain,omitempty"`
+	Version   string `json:"version"`
+	Detail    string `json:"detail"`
+}
+
+// Extra versions are only reported, every other drift needs fixing.
+func (d ManifestDrift) IsProblem() bool {
+	return d.Kind != DriftExtra
+}
+
+// Outcome of downloading a single manifest toolchain.
+type SyncResult struct {
+	Toolchain   ManifestToolchain
+	Remote      *RemoteVersion
+	ArchivePath string
+	Signed      bool
+	Err         error
+}
+
+// Platform of the running gvm binary, e.g. "linux/amd64".
+func CurrentPlatform() string {
+	return runtime.GOOS + "/" + runtime.GOARCH
+}
+
+// Finds gvm.toml in dir or one of its parents.
+func FindManifest(dir string) (string, bool) {
+	return findUp(dir, ManifestFileName)
+}
+
+// Reads and validates a manifest file.
+func LoadManifest(path string) (*Manifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("Manifest Error: %w", err)
+	}
+
+	document, err := ParseTOML(data)
+	if err != nil {
+		return nil, fmt.Errorf("Manifest Error: %s: %w", path, err)
+	}
+
+	manifest, err := parseManifest(document)
+	if err != nil {
+		return nil, fmt.Errorf("Manifest Error: %s: %w", path, err)
+	}
+	manifest.Path = path
+
+	return manifest, nil
+}
+
+func parseManifest(document map[string]any) (*Manifest, error) {
+	manifest := &Manifest{}
+
+	for key, value := range document {
+		switch key {
+		case "platforms":
+			platforms, err := parsePlatformList(key, value)
+			if err != nil {
+				return nil, err
+			}
+			manifest.Platforms = platforms
+		case "toolchains":
+			table, ok := value.(map[string]any)
+			if !ok {
+				return nil, fmt.Errorf("'toolchains' must be a table")
+			}
+			for name, entry := range table {
+				toolchain, err := parseManifestToolchain(name, entry)
+				if err != nil {
+					return nil, err
+				}
+				manifest.Toolchains = append(manifest.Toolchains, toolchain)
+			}
+		default:
+			return nil, fmt.Errorf("unknown key '%s'", key)
+		}
+	}
+
+	if len(manifest.Toolchains) == 0 {
+		return nil, fmt.Errorf("no toolchains defined, add a [toolchains.<name>] table")
+	}
+
+	// platforms may follow the toolchains in the document
+	for i := range manifest.Toolchains {
+		toolchain := &manifest.Toolchains[i]
+		if toolchain.Platforms == nil {
+			toolchain.Platforms = manifest.Platforms
+		}
+		for platform := range toolchain.SHA256 {
+			if !toolchain.HasPlatform(platform) {
+				return nil, fmt.Errorf("toolchain '%s' pins a checksum for %s which is not one of its platforms", toolchain.Name, platform)
+			}
+		}
+	}
+
+	sort.Slice(manifest.Toolchains, func(i, j int) bool {
+		return manifest.Toolchains[i].Name < manifest.Toolchains[j].Name
+	})
+
+	return manifest, nil
+}
+
+func parseManifestToolchain(name string, value any) (ManifestToolchain, error) {
+	toolchain := ManifestToolchain{Name: name, SHA256: make(map[string]string)}
+
+	table, ok := value.(map[string]any)
+	if !ok {
+		return toolchain, fmt.Errorf("toolchain '%s' must be a table", name)
+	}
+
+	for key, value := range table {
+		switch key {
+		case "version":
+			s, ok := value.(string)
+			if !ok {
+				return toolchain, fmt.Errorf("toolchain '%s': 'version' must be a string", name)
+			}
+			version, err := parseExactRelease(s)
+			if err != nil {
+				return toolchain, fmt.Errorf("toolchain '%s': %w", name, err)
+			}
+			toolchain.Version = version.String()
+		case "platforms":
+			platforms, err := parsePlatformList(name+".platforms", value)
+			if err != nil {
+				return toolchain, err
+			}
+			toolchain.Platforms = platforms
+		case "sha256":
+			checksums, ok := value.(map[string]any)
+			if !ok {
+				return toolchain, fmt.Errorf("toolchain '%s': 'sha256' must be a table of platform = checksum", name)
+			}
+			for platform, checksum := range checksums {
+				s, ok := checksum.(string)
+				if !ok || !isSHA256Hex(s) {
+					return toolchain, fmt.Errorf("toolchain '%s': invalid sha256 for %s", name, platform)
+				}
+				if err := validatePlatform(platform); err != nil {
+					return toolchain, fmt.Errorf("toolchain '%s': %w", name, err)
+				}
+				toolchain.SHA256[platform] = strings.ToLower(s)
+			}
+		default:
+			return toolchain, fmt.Errorf("toolchain '%s': unknown key '%s'", name, key)
+		}
+	}
+
+	if toolchain.Version == "" {
+		return toolchain, fmt.Errorf("toolchain '%s' has no version", name)
+	}
+
+	return toolchain, nil
+}
+
+// Parses a version which names exactly one release. "1.25" is rejected
+// since go1.21 and later name their first release "1.25.0".
+func parseExactRelease(s string) (GoVersion, error) {
+	version, err := ParseGoVersion(s)
+	if err != nil {
+		return version, err
+	}
+
+	if !version.HasPatch() && !version.IsPrerelease() && (version.Major > 1 || version.Minor >= 21) {
+		return version, fmt.Errorf("version '%s' is not an exact release, e.g. %s.0", s, version.Number())
+	}
+
+	return version, nil
+}
+
+func parsePlatformList(key string, value any) ([]string, error) {
+	values, ok := value.([]any)
+	if !ok {
+		return nil, fmt.Errorf("'%s' must be an array of \"goos/goarch\" strings", key)
+	}
+
+	platforms := make([]string, 0, len(values))
+	for _, value := range values {
+		platform, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("'%s' must be an array of \"goos/goarch\" strings", key)
+		}
+		if err := validatePlatform(platform); err != nil {
+			return nil, err
+		}
+		platforms = append(platforms, platform)
+	}
+
+	return platforms, nil
+}
+
+func validatePlatform(platform string) error {
+	goos, goarch, ok := strings.Cut(platform, "/")
+	if !ok || goos == "" || goarch == "" || strings.Contains(goarch, "/") {
+		return fmt.Errorf("invalid platform '%s', expected goos/goarch, e.g. linux/amd64", platform)
+	}
+	return nil
+}
+
+func isSHA256Hex(s string) bool {
+	decoded, err := hex.DecodeString(s)
+	return err == nil && len(decoded) == 32
+}
+
+// Reports whether the toolchain is needed on the platform. Toolchains
+// without platforms are needed everywhere.
+func (t ManifestToolchain) HasPlatform(platform string) bool {
+	return len(t.Platforms) == 0 || slices.Contains(t.Platforms, platform)
+}
+
+// Toolchains needed on the platform.
+func (m *Manifest) ToolchainsFor(platform string) []ManifestToolchain {
+	toolchains := make([]ManifestToolchain, 0, len(m.Toolchains))
+	for _, toolchain := range m.Toolchains {
+		if toolchain.HasPlatform(platform) {
+			toolchains = append(toolchains, toolchain)
+		}
+	}
+	return toolchains
+}
+
+// Compares the installed versions with the toolchains the manifest
+// requires on the running platform.
+func (c *Config) ManifestDrift(m *Manifest) []ManifestDrift {
+	platform := CurrentPlatform()
+	drifts := make([]ManifestDrift, 0)
+
+	for _, toolchain := range m.ToolchainsFor(platform) {
+		downloaded := c.ResolveDownloadedVersion(toolchain.Version)
+		switch {
+		case downloaded == nil:
+			drifts = append(drifts, ManifestDrift{
+				Kind:      DriftMissing,
+				Toolchain: toolchain.Name,
+				Version:   toolchain.Version,
+				Detail:    "not installed",
+			})
+		case downloaded.IsMissing():
+			drifts = append(drifts, ManifestDrift{
+				Kind:      DriftMissing,
+				Toolchain: toolchain.Name,
+				Version:   toolchain.Version,
+				Detail:    "installed but its files are missing",
+			})
+		case toolchain.SHA256[platform] != "" && !downloaded.IsLink():
+			checksum := downloaded.SHA256
+			if checksum == "" {
+				checksum, _ = FileSHA256(downloaded.TarPath)
+			}
+			if !strings.EqualFold(checksum, toolchain.SHA256[platform]) {
+				drifts = append(drifts, ManifestDrift{
+					Kind:      DriftChecksum,
+					Toolchain: toolchain.Name,
+					Version:   toolchain.Version,
+					Detail:    fmt.Sprintf("installed archive has sha256 %s, %s pins %s", ShortChecksum(checksum), ManifestFileName, ShortChecksum(toolchain.SHA256[platform])),
+				})
+			}
+		}
+	}
+
+	for _, downloaded := range *c.GetDownloadedVersions() {
+		if downloaded.IsLink() {
+			continue
+		}
+
+		listed := slices.ContainsFunc(m.Toolchains, func(t ManifestToolchain) bool {
+			return SameGoVersion(t.Version, downloaded.Version)
+		})
+		if !listed {
+			drifts = append(drifts, ManifestDrift{
+				Kind:    DriftExtra,
+				Version: downloaded.Version,
+				Detail:  fmt.Sprintf("installed but not listed in %s", ManifestFileName),
+			})
+		}
+	}
+
+	return drifts
+}
+
+// Downloads the toolchains with up to jobs parallel downloads. Every
+// archive is checked against the pinned checksum, the version it claims
+// to contain and the configured signature keys. Registering the results
+// with MarkVersionAsDownloaded is left to the caller.
+func (c *Config) SyncManifestToolchains(toolchains []ManifestToolchain, jobs int, requireSignature bool) []SyncResult {
+	results := make([]SyncResult, len(toolchains))
+	var index []RemoteVersion
+	var indexErr error
+	indexFetched := false
+
+	for i, toolchain := range toolchains {
+		results[i].Toolchain = toolchain
+
+		remote := c.FindAvailableVersion(toolchain.Version)
+		if remote == nil {
+			// the cached index only holds the newest releases
+			if !indexFetched {
+				index, indexErr = c.fetchFullIndex()
+				indexFetched = true
+			}
+			remote = findRemoteVersion(index, toolchain.Version)
+		}
+		if remote == nil {
+			if indexErr != nil {
+				results[i].Err = fmt.Errorf("Sync Error: %s is not in the cached version list and the version source failed: %w", toolchain.Version, indexErr)
+			} else {
+				results[i].Err = fmt.Errorf("Sync Error: %s is not available for %s from the version source", toolchain.Version, CurrentPlatform())
+			}
+			continue
+		}
+
+		pinned := toolchain.SHA256[CurrentPlatform()]
+		if pinned != "" {
+			if remote.SHA256 != "" && !strings.EqualFold(remote.SHA256, pinned) {
+				results[i].Err = fmt.Errorf("Checksum Error: %s pins sha256 %s for %s but the version source lists %s", ManifestFileName, pinned, toolchain.Version, remote.SHA256)
+				continue
+			}
+			remote.SHA256 = pinned
+		}
+		results[i].Remote = remote
+	}
+
+	if jobs < 1 {
+		jobs = 1
+	}
+	semaphore := make(chan struct{}, jobs)
+	var wg sync.WaitGroup
+
+	for i := range results {
+		if results[i].Err != nil {
+			continue
+		}
+
+		wg.Add(1)
+		go func(result *SyncResult) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			result.ArchivePath, result.Signed, result.Err = c.DownloadVerified(result.Remote, jobs == 1, requireSignature)
+		}(&results[i])
+	}
+	wg.Wait()
+
+	return results
+}
+
+// Fetches every release of the configured source, not only the newest
+// ones kept in AvailableVersions.
+func (c *Config) fetchFullIndex() ([]RemoteVersion, error) {
+	source, err := c.GetVersionSource()
+	if err != nil {
+		return nil, err
+	}
+	return source.FetchVersions()
+}
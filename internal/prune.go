@@ -0,0 +1,226 @@
+package internal
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// Number of most recent minor releases supported by the go team.
+const SupportedMinorReleases = 2
+
+// Policies deciding which downloaded versions `gvm prune` removes.
+// A version is removed when any enabled policy selects it.
+type PrunePolicy struct {
+	// keep only the newest N patch releases of every minor, 0 disables
+	KeepPatches int
+	// remove versions older than the supported minor releases
+	RemoveUnsupported bool
+	// remove versions unused for longer than this, 0 disables
+	UnusedFor time.Duration
+}
+
+// A downloaded version selected for removal and why.
+type PruneCandidate struct {
+	Version DownloadVersion
+	Reason  string
+}
+
+// Disk space taken by a downloaded version.
+type DiskUsage struct {
+	Version DownloadVersion
+	// size of the tarball (shared with the download cache when hardlinked)
+	ArchiveSize int64
+	// size of the extracted toolchain used by build, exec and bootstrap
+	ExtractedSize int64
+}
+
+func (du DiskUsage) Total() int64 {
+	return du.ArchiveSize + du.ExtractedSize
+}
+
+// Returns when the version was last used. Versions without recorded
+// usage fall back to their installation time and then to the
+// modification time of their tarball.
+func (dv *DownloadVersion) LastUsed() time.Time {
+	if dv.LastUsedAt > 0 {
+		return time.UnixMilli(dv.LastUsedAt)
+	}
+	if dv.InstalledAt > 0 {
+		return time.UnixMilli(dv.InstalledAt)
+	}
+	if info, err := os.Stat(dv.TarPath); err == nil {
+		return info.ModTime()
+	}
+	return time.Time{}
+}
+
+// Measures the disk usage of every downloaded version, linked versions
+// are reported with zero size as gvm doesn't own their files.
+func (c *Config) GetDiskUsage() []DiskUsage {
+	usages := make([]DiskUsage, 0, len(c.DownloadedVersions))
+
+	for _, downloadVersion := range *c.GetDownloadedVersions() {
+		usage := DiskUsage{Version: downloadVersion}
+		if !downloadVersion.IsLink() {
+			if info, err := os.Stat(downloadVersion.TarPath); err == nil {
+				usage.ArchiveSize = info.Size()
+			}
+			if toolchainDir, err := downloadVersion.ToolchainDir(); err == nil {
+				usage.ExtractedSize = DirSize(toolchainDir)
+			}
+		}
+		usages = append(usages, usage)
+	}
+
+	return usages
+}
+
+// Sums the size of the regular files below path, zero if it is missing.
+func DirSize(path string) int64 {
+	var size int64
+
+	filepath.WalkDir(path, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.Type().IsRegular() {
+			if info, err := d.Info(); err == nil {
+				size += info.Size()
+			}
+		}
+		return nil
+	})
+
+	return size
+}
+
+// Parses a stable release name like "go1.25.5". Prereleases, devel
+// builds and linked names aren't releases.
+func parseReleaseVersion(version string) (GoVersion, bool) {
+	v, err := ParseGoVersion(version)
+	if err != nil || v.IsPrerelease() {
+		return GoVersion{}, false
+	}
+	return v, true
+}
+
+// Selects the downloaded versions the policy removes. Versions listed in
+// protected (e.g. the active and the default one, in any spelling like
+// "1.25.5") and linked versions are never selected.
+func (c *Config) SelectPruneCandidates(policy PrunePolicy, protected []string, now time.Time) []PruneCandidate {
+	isProtected := make(map[string]bool)
+	for _, version := range protected {
+		if downloadVersion := c.ResolveDownloadedVersion(version); downloadVersion != nil {
+			isProtected[downloadVersion.Version] = true
+		}
+	}
+
+	type release struct {
+		version      DownloadVersion
+		goVersion    GoVersion
+		isReleaseVer bool
+	}
+
+	releases := make([]release, 0)
+	minors := make(map[string]GoVersion)
+	for _, downloadVersion := range c.DownloadedVersions {
+		goVersion, ok := parseReleaseVersion(downloadVersion.Version)
+		releases = append(releases, release{version: downloadVersion, goVersion: goVersion, isReleaseVer: ok})
+		if ok {
+			minors[goVersion.MinorLine()] = goVersion
+		}
+	}
+
+	// supported minors are derived from the remote index when possible
+	for _, remoteVersion := range c.AvailableVersions {
+		if goVersion, ok := parseReleaseVersion(remoteVersion.Version); ok {
+			minors[goVersion.MinorLine()] = goVersion
+		}
+	}
+	minorLines := make([]GoVersion, 0, len(minors))
+	for _, goVersion := range minors {
+		minorLines = append(minorLines, GoVersion{Major: goVersion.Major, Minor: goVersion.Minor})
+	}
+	sort.Slice(minorLines, func(i, j int) bool {
+		return minorLines[j].Less(minorLines[i])
+	})
+	var oldestSupported *GoVersion
+	if len(minorLines) >= SupportedMinorReleases {
+		oldestSupported = &minorLines[SupportedMinorReleases-1]
+	}
+
+	// newest release first, versions which aren't releases last
+	sort.SliceStable(releases, func(i, j int) bool {
+		if releases[i].isReleaseVer != releases[j].isReleaseVer {
+			return releases[i].isReleaseVer
+		}
+		return releases[j].goVersion.Less(releases[i].goVersion)
+	})
+
+	candidates := make([]PruneCandidate, 0)
+	keptPerMinor := make(map[string]int)
+
+	for _, r := range releases {
+		minorLine := r.goVersion.MinorLine()
+
+		if r.version.IsLink() || isProtected[r.version.Version] {
+			if r.isReleaseVer {
+				keptPerMinor[minorLine]++
+			}
+			continue
+		}
+
+		reason := ""
+		if r.isReleaseVer && policy.RemoveUnsupported && oldestSupported != nil && r.goVersion.Less(*oldestSupported) {
+			reason = fmt.Sprintf("%s is no longer supported", minorLine)
+		} else if r.isReleaseVer && policy.KeepPatches > 0 && keptPerMinor[minorLine] >= policy.KeepPatches {
+			reason = fmt.Sprintf("%d newer patch release(s) of %s are kept", policy.KeepPatches, minorLine)
+		} else if policy.UnusedFor > 0 {
+			if lastUsed := r.version.LastUsed(); !lastUsed.IsZero() && now.Sub(lastUsed) > policy.UnusedFor {
+				reason = fmt.Sprintf("unused since %s", lastUsed.Format("2006-01-02"))
+			}
+		}
+
+		if reason == "" {
+			if r.isReleaseVer {
+				keptPerMinor[minorLine]++
+			}
+			continue
+		}
+
+		candidates = append(candidates, PruneCandidate{Version: r.version, Reason: reason})
+	}
+
+	return candidates
+}
+
+// Removes a downloaded version from the config together with its tarball
+// in the per user view and its extracted toolchain. The shared download
+// cache is left alone, see `gvm cache prune`.
+func (c *Config) RemoveDownloadedVersion(version string) error {
+	downloadVersion, exists := c.DownloadedVersions[version]
+	if !exists {
+		return fmt.Errorf("Config Error: version %s is not downloaded", version)
+	}
+
+	if !downloadVersion.IsLink() {
+		if err := os.Remove(downloadVersion.TarPath); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("IO Error: failed to remove %s: %w", downloadVersion.TarPath, err)
+		}
+
+		if toolchainDir, err := downloadVersion.ToolchainDir(); err == nil {
+			if err := os.RemoveAll(toolchainDir); err != nil {
+				return fmt.Errorf("IO Error: failed to remove %s: %w", toolchainDir, err)
+			}
+		}
+	}
+
+	return c.update(func(latest *Config) error {
+		delete(latest.DownloadedVersions, version)
+		return nil
+	})
+}
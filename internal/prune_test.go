@@ -0,0 +1,110 @@
+package internal
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestSelectPruneCandidates(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	recently := now.Add(-24 * time.Hour).UnixMilli()
+	longAgo := now.Add(-60 * 24 * time.Hour).UnixMilli()
+
+	downloaded := map[string]DownloadVersion{
+		"go1.26rc1": {Version: "go1.26rc1", LastUsedAt: longAgo},
+		"go1.25.5":  {Version: "go1.25.5", LastUsedAt: recently},
+		"go1.25.3":  {Version: "go1.25.3", LastUsedAt: recently},
+		"go1.25.0":  {Version: "go1.25.0", LastUsedAt: longAgo},
+		"go1.24.2":  {Version: "go1.24.2", LastUsedAt: recently},
+		"go1.24.1":  {Version: "go1.24.1", LastUsedAt: recently},
+		"go1.23.4":  {Version: "go1.23.4", LastUsedAt: longAgo},
+		"system":    {Version: "system", LinkPath: "/usr/lib/go", LastUsedAt: longAgo},
+		"go1.22.0":  {Version: "go1.22.0", LinkPath: "/opt/go1.22", LastUsedAt: longAgo},
+	}
+
+	tests := []struct {
+		name      string
+		policy    PrunePolicy
+		protected []string
+		available []string
+		want      []string
+	}{
+		{
+			name:   "keep newest patch",
+			policy: PrunePolicy{KeepPatches: 1},
+			want:   []string{"go1.24.1", "go1.25.0", "go1.25.3"},
+		},
+		{
+			name:   "keep two patches",
+			policy: PrunePolicy{KeepPatches: 2},
+			want:   []string{"go1.25.0"},
+		},
+		{
+			name:      "protected older patch",
+			policy:    PrunePolicy{KeepPatches: 1},
+			protected: []string{"go1.25.0"},
+			want:      []string{"go1.24.1", "go1.25.3"},
+		},
+		{
+			name:      "protected in another spelling",
+			policy:    PrunePolicy{KeepPatches: 1},
+			protected: []string{"1.25.3", " go1.24.1 ", "1.27"},
+			want:      []string{"go1.25.0"},
+		},
+		{
+			name:   "unsupported from downloaded minors",
+			policy: PrunePolicy{RemoveUnsupported: true},
+			want:   []string{"go1.23.4"},
+		},
+		{
+			name:      "unsupported from remote index",
+			policy:    PrunePolicy{RemoveUnsupported: true},
+			available: []string{"go1.26.0", "go1.26rc1", "go1.25.5"},
+			want:      []string{"go1.23.4", "go1.24.1", "go1.24.2"},
+		},
+		{
+			name:      "protected unsupported",
+			policy:    PrunePolicy{RemoveUnsupported: true},
+			protected: []string{"1.23.4"},
+		},
+		{
+			name:   "unused",
+			policy: PrunePolicy{UnusedFor: 30 * 24 * time.Hour},
+			want:   []string{"go1.23.4", "go1.25.0", "go1.26rc1"},
+		},
+		{
+			name:      "combined policies",
+			policy:    PrunePolicy{KeepPatches: 1, RemoveUnsupported: true, UnusedFor: 30 * 24 * time.Hour},
+			protected: []string{"go1.26rc1"},
+			want:      []string{"go1.23.4", "go1.24.1", "go1.25.0", "go1.25.3"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			config := &Config{DownloadedVersions: downloaded}
+			for _, version := range test.available {
+				config.AvailableVersions = append(config.AvailableVersions, RemoteVersion{Version: version})
+			}
+
+			got := make([]string, 0)
+			for _, candidate := range config.SelectPruneCandidates(test.policy, test.protected, now) {
+				if candidate.Reason == "" {
+					t.Errorf("%s selected without a reason", candidate.Version.Version)
+				}
+				got = append(got, candidate.Version.Version)
+			}
+			sort.Strings(got)
+
+			want := test.want
+			if want == nil {
+				want = []string{}
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("SelectPruneCandidates() = %v, want %v", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,271 @@
+package internal
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	progressbar "github.com/schollz/progressbar/v3"
+)
+
+// Release feed of gvm itself, in the format of the github releases api.
+const GVM_RELEASES_URL = "https://api.github.com/repos/vilayat-ali/gvm/releases"
+
+// Release channels of gvm
+const (
+	SelfUpdateChannelStable = "stable"
+	SelfUpdateChannelBeta   = "beta"
+)
+
+// Name of the checksum file published with every gvm release.
+const selfChecksumAsset = "SHA256SUMS.txt"
+
+// A release of gvm built for the running platform.
+type SelfRelease struct {
+	Version      string
+	ArchiveName  string
+	ArchiveURL   string
+	ChecksumsURL string
+}
+
+type selfReleaseFeedEntry struct {
+	TagName    string `json:"tag_name"`
+	Prerelease bool   `json:"prerelease"`
+	Draft      bool   `json:"draft"`
+	Assets     []struct {
+		Name        string `json:"name"`
+		DownloadURL string `json:"browser_download_url"`
+	} `json:"assets"`
+}
+
+// Name of the gvm binary inside the release archives, as built by
+// `make release`.
+func selfBinaryName(goos string, goarch string) string {
+	name := fmt.Sprintf("%s-%s-%s", AppName, goos, goarch)
+	if goos == "windows" {
+		name += ".exe"
+	}
+	return name
+}
+
+// Finds the newest release of the channel providing an archive for the
+// running platform. The beta channel includes prereleases.
+func FindSelfRelease(feedURL string, channel string) (*SelfRelease, error) {
+	if feedURL == "" {
+		feedURL = GVM_RELEASES_URL
+	}
+	if channel == "" {
+		channel = SelfUpdateChannelStable
+	}
+	if channel != SelfUpdateChannelStable && channel != SelfUpdateChannelBeta {
+		return nil, fmt.Errorf("Input Error: unknown channel '%s', expected %s or %s", channel, SelfUpdateChannelStable, SelfUpdateChannelBeta)
+	}
+
+	response, err := HTTPClient().Get(feedURL)
+	if err != nil {
+		return nil, fmt.Errorf("Update Error: %w", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("Update Error: failed to fetch %s. Status: %s", feedURL, response.Status)
+	}
+
+	var entries []selfReleaseFeedEntry
+	if err := json.NewDecoder(response.Body).Decode(&entries); err != nil {
+		return nil, fmt.Errorf("Update Error: failed to parse release feed: %w", err)
+	}
+
+	archivePrefix := fmt.Sprintf("%s-%s-%s-", AppName, runtime.GOOS, runtime.GOARCH)
+
+	var newest *SelfRelease
+	for _, entry := range entries {
+		if entry.Draft || (entry.Prerelease && channel != SelfUpdateChannelBeta) {
+			continue
+		}
+
+		release := SelfRelease{Version: strings.TrimPrefix(entry.TagName, "v")}
+		for _, asset := range entry.Assets {
+			switch {
+			case asset.Name == selfChecksumAsset:
+				release.ChecksumsURL = asset.DownloadURL
+			case strings.HasPrefix(asset.Name, archivePrefix):
+				release.ArchiveName = asset.Name
+				release.ArchiveURL = asset.DownloadURL
+			}
+		}
+
+		if release.ArchiveURL == "" {
+			continue
+		}
+		if newest == nil || IsNewerSelfVersion(newest.Version, release.Version) {
+			newest = &release
+		}
+	}
+
+	if newest == nil {
+		return nil, fmt.Errorf("Update Error: no %s release found for %s/%s", channel, runtime.GOOS, runtime.GOARCH)
+	}
+
+	return newest, nil
+}
+
+// Reports whether candidate is a newer gvm version than current.
+func IsNewerSelfVersion(current string, candidate string) bool {
+	currentVersion, currentOk := parseSemver(current)
+	candidateVersion, candidateOk := parseSemver(candidate)
+	return currentOk && candidateOk && currentVersion.Less(candidateVersion)
+}
+
+// Downloads the release archive, verifies it against the published
+// checksums and extracts the gvm binary. The checksums themselves are
+// verified with the signature keys of the config. Returns the path of
+// the binary in a temporary directory which the caller removes and
+// whether the checksums were signed.
+func (r *SelfRelease) Download(c *Config) (string, string, bool, error) {
+	if r.ChecksumsURL == "" {
+		return "", "", false, fmt.Errorf("Update Error: release %s publishes no %s, refusing to install an unverified binary", r.Version, selfChecksumAsset)
+	}
+
+	tmpDir, err := os.MkdirTemp("", "gvm-self-update-")
+	if err != nil {
+		return "", "", false, fmt.Errorf("Update Error: %w", err)
+	}
+
+	binaryPath, signed, err := r.download(c, tmpDir)
+	if err != nil {
+		os.RemoveAll(tmpDir)
+		return "", "", false, err
+	}
+
+	return binaryPath, tmpDir, signed, nil
+}
+
+func (r *SelfRelease) download(c *Config, tmpDir string) (string, bool, error) {
+	checksums, err := fetchBytes(r.ChecksumsURL)
+	if err != nil {
+		return "", false, err
+	}
+
+	checksumsPath := filepath.Join(tmpDir, selfChecksumAsset)
+	if err := os.WriteFile(checksumsPath, checksums, 0644); err != nil {
+		return "", false, fmt.Errorf("Update Error: %w", err)
+	}
+	signed, err := c.VerifySignature(checksumsPath, RemoteSignatureLoader(r.ChecksumsURL), false)
+	if err != nil {
+		return "", false, err
+	}
+
+	expected := ""
+	scanner := bufio.NewScanner(bytes.NewReader(checksums))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && strings.TrimPrefix(fields[1], "*") == r.ArchiveName {
+			expected = fields[0]
+		}
+	}
+	if expected == "" {
+		return "", false, fmt.Errorf("Update Error: %s has no checksum for %s", selfChecksumAsset, r.ArchiveName)
+	}
+
+	archivePath := filepath.Join(tmpDir, r.ArchiveName)
+	if err := downloadFile(r.ArchiveURL, archivePath); err != nil {
+		return "", false, err
+	}
+
+	if err := VerifyFileChecksum(archivePath, expected); err != nil {
+		return "", false, err
+	}
+
+	extractDir := filepath.Join(tmpDir, "extracted")
+	if err := ExtractArchive(archivePath, extractDir); err != nil {
+		return "", false, err
+	}
+
+	binaryPath := filepath.Join(extractDir, selfBinaryName(runtime.GOOS, runtime.GOARCH))
+	if _, err := os.Stat(binaryPath); err != nil {
+		return "", false, fmt.Errorf("Update Error: %s not found in %s", filepath.Base(binaryPath), r.ArchiveName)
+	}
+
+	return binaryPath, signed, nil
+}
+
+func downloadFile(url string, path string) error {
+	response, err := HTTPClient().Get(url)
+	if err != nil {
+		return fmt.Errorf("Update Error: %w", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("Update Error: failed to download %s. Status: %s", url, response.Status)
+	}
+
+	out, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("Update Error: %w", err)
+	}
+	defer out.Close()
+
+	progress := progressbar.DefaultBytes(response.ContentLength, "downloading")
+	if _, err := io.Copy(io.MultiWriter(out, progress), response.Body); err != nil {
+		return fmt.Errorf("Update Error: %w", err)
+	}
+
+	return out.Close()
+}
+
+// Atomically replaces the running executable with newBinary. The new
+// binary is staged next to the executable and renamed over it, so the
+// executable is never missing or partially written.
+func ReplaceExecutable(newBinary string) error {
+	executable, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("Update Error: failed to locate the gvm executable: %w", err)
+	}
+	if resolved, err := filepath.EvalSymlinks(executable); err == nil {
+		executable = resolved
+	}
+
+	return replaceFile(executable, newBinary)
+}
+
+func replaceFile(executable string, newBinary string) error {
+	info, err := os.Stat(executable)
+	if err != nil {
+		return fmt.Errorf("Update Error: %w", err)
+	}
+
+	staged := executable + ".new"
+	if err := copyFile(newBinary, staged); err != nil {
+		return fmt.Errorf("Update Error: failed to stage new binary next to %s: %w", executable, err)
+	}
+	if err := os.Chmod(staged, info.Mode().Perm()|0111); err != nil {
+		os.Remove(staged)
+		return fmt.Errorf("Update Error: %w", err)
+	}
+
+	// a running executable can't be replaced on windows, move it aside first
+	if runtime.GOOS == "windows" {
+		old := executable + ".old"
+		os.Remove(old)
+		if err := os.Rename(executable, old); err != nil {
+			os.Remove(staged)
+			return fmt.Errorf("Update Error: %w", err)
+		}
+	}
+
+	if err := os.Rename(staged, executable); err != nil {
+		os.Remove(staged)
+		return fmt.Errorf("Update Error: failed to replace %s: %w", executable, err)
+	}
+
+	return nil
+}
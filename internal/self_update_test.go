@@ -0,0 +1,256 @@
+package internal
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+type testFeedAsset struct {
+	Name        string `json:"name"`
+	DownloadURL string `json:"browser_download_url"`
+}
+
+type testFeedEntry struct {
+	TagName    string          `json:"tag_name"`
+	Prerelease bool            `json:"prerelease"`
+	Draft      bool            `json:"draft"`
+	Assets     []testFeedAsset `json:"assets"`
+}
+
+func testSelfArchiveName(version string, goos string, goarch string) string {
+	return fmt.Sprintf("%s-%s-%s-%s.tar.gz", AppName, goos, goarch, version)
+}
+
+func TestFindSelfRelease(t *testing.T) {
+	platformAssets := func(version string) []testFeedAsset {
+		name := testSelfArchiveName(version, runtime.GOOS, runtime.GOARCH)
+		return []testFeedAsset{
+			{Name: selfChecksumAsset, DownloadURL: "https://example.com/" + version + "/" + selfChecksumAsset},
+			{Name: testSelfArchiveName(version, "plan9", "arm"), DownloadURL: "https://example.com/plan9"},
+			{Name: name, DownloadURL: "https://example.com/" + version + "/" + name},
+		}
+	}
+	feed := []testFeedEntry{
+		{TagName: "v1.9.0", Assets: platformAssets("1.9.0")},
+		{TagName: "v2.0.0", Draft: true, Assets: platformAssets("2.0.0")},
+		{TagName: "v1.12.0", Assets: []testFeedAsset{{Name: testSelfArchiveName("1.12.0", "plan9", "arm"), DownloadURL: "https://example.com/plan9"}}},
+		{TagName: "v1.11.0-rc.1", Prerelease: true, Assets: platformAssets("1.11.0-rc.1")},
+		{TagName: "v1.10.0", Assets: platformAssets("1.10.0")},
+		{TagName: "v1.2.0", Assets: platformAssets("1.2.0")},
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/releases":
+			json.NewEncoder(w).Encode(feed)
+		case "/empty":
+			w.Write([]byte("[]"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name    string
+		path    string
+		channel string
+		want    string
+		wantErr bool
+	}{
+		{name: "stable", path: "/releases", channel: SelfUpdateChannelStable, want: "1.10.0"},
+		{name: "default channel", path: "/releases", want: "1.10.0"},
+		{name: "beta includes prereleases", path: "/releases", channel: SelfUpdateChannelBeta, want: "1.11.0-rc.1"},
+		{name: "unknown channel", path: "/releases", channel: "nightly", wantErr: true},
+		{name: "no release", path: "/empty", wantErr: true},
+		{name: "feed unavailable", path: "/missing", wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			release, err := FindSelfRelease(server.URL+test.path, test.channel)
+			if test.wantErr {
+				if err == nil {
+					t.Fatalf("FindSelfRelease() = %+v, want error", release)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("FindSelfRelease() = %v", err)
+			}
+
+			wantArchive := testSelfArchiveName(test.want, runtime.GOOS, runtime.GOARCH)
+			if release.Version != test.want || release.ArchiveName != wantArchive {
+				t.Errorf("FindSelfRelease() = %s (%s), want %s (%s)", release.Version, release.ArchiveName, test.want, wantArchive)
+			}
+			if !strings.HasSuffix(release.ArchiveURL, "/"+test.want+"/"+wantArchive) || !strings.HasSuffix(release.ChecksumsURL, "/"+test.want+"/"+selfChecksumAsset) {
+				t.Errorf("FindSelfRelease() urls = %s, %s", release.ArchiveURL, release.ChecksumsURL)
+			}
+		})
+	}
+}
+
+func TestSelfReleaseDownload(t *testing.T) {
+	binaryName := selfBinaryName(runtime.GOOS, runtime.GOARCH)
+	archiveName := testSelfArchiveName("1.10.0", runtime.GOOS, runtime.GOARCH)
+	dir := t.TempDir()
+
+	archivePath := filepath.Join(dir, archiveName)
+	writeTestTarGz(t, archivePath, []archiveEntry{{name: binaryName, body: "new gvm", mode: 0755}})
+	archiveSHA, err := FileSHA256(archivePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	emptyArchivePath := filepath.Join(dir, "empty.tar.gz")
+	writeTestTarGz(t, emptyArchivePath, []archiveEntry{{name: "README", body: "no binary", mode: 0644}})
+	emptySHA, err := FileSHA256(emptyArchivePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKIXPublicKey(publicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyPath := filepath.Join(dir, "release.pem")
+	if err := os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	checksumsFor := func(sha string) string {
+		return fmt.Sprintf("%s  other.tar.gz\n%s *%s\n", strings.Repeat("0", 64), sha, archiveName)
+	}
+	files := map[string]string{
+		"/good/" + selfChecksumAsset:                   checksumsFor(archiveSHA),
+		"/bad-sum/" + selfChecksumAsset:                checksumsFor(strings.Repeat("1", 64)),
+		"/no-entry/" + selfChecksumAsset:               fmt.Sprintf("%s  other.tar.gz\n", archiveSHA),
+		"/no-binary/" + selfChecksumAsset:              checksumsFor(emptySHA),
+		"/signed/" + selfChecksumAsset:                 checksumsFor(archiveSHA),
+		"/bad-sig/" + selfChecksumAsset:                checksumsFor(archiveSHA),
+		"/bad-sig/" + selfChecksumAsset + SignatureExt: string(ed25519.Sign(privateKey, []byte("something else"))),
+	}
+	files["/signed/"+selfChecksumAsset+SignatureExt] = string(ed25519.Sign(privateKey, []byte(files["/signed/"+selfChecksumAsset])))
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasPrefix(r.URL.Path, "/no-binary/") && strings.HasSuffix(r.URL.Path, ".tar.gz"):
+			http.ServeFile(w, r, emptyArchivePath)
+		case strings.HasSuffix(r.URL.Path, "/"+archiveName):
+			http.ServeFile(w, r, archivePath)
+		default:
+			content, ok := files[r.URL.Path]
+			if !ok {
+				http.NotFound(w, r)
+				return
+			}
+			w.Write([]byte(content))
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name       string
+		dir        string
+		noSums     bool
+		config     Config
+		wantSigned bool
+		wantErr    string
+	}{
+		{name: "verified", dir: "good"},
+		{name: "unsigned with keys", dir: "good", config: Config{SignatureKeys: []string{keyPath}}},
+		{name: "unsigned with signatures required", dir: "good", config: Config{SignatureKeys: []string{keyPath}, RequireSignature: true}, wantErr: "Signature Error"},
+		{name: "signed", dir: "signed", config: Config{SignatureKeys: []string{keyPath}, RequireSignature: true}, wantSigned: true},
+		{name: "invalid signature", dir: "bad-sig", config: Config{SignatureKeys: []string{keyPath}}, wantErr: "invalid signature"},
+		{name: "checksum mismatch", dir: "bad-sum", wantErr: "Checksum Error"},
+		{name: "no checksum for the archive", dir: "no-entry", wantErr: "has no checksum for"},
+		{name: "no SHA256SUMS.txt", dir: "good", noSums: true, wantErr: "publishes no " + selfChecksumAsset},
+		{name: "SHA256SUMS.txt unavailable", dir: "missing", wantErr: "404"},
+		{name: "binary missing in the archive", dir: "no-binary", wantErr: binaryName + " not found"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			release := &SelfRelease{
+				Version:      "1.10.0",
+				ArchiveName:  archiveName,
+				ArchiveURL:   server.URL + "/" + test.dir + "/" + archiveName,
+				ChecksumsURL: server.URL + "/" + test.dir + "/" + selfChecksumAsset,
+			}
+			if test.noSums {
+				release.ChecksumsURL = ""
+			}
+
+			binaryPath, tmpDir, signed, err := release.Download(&test.config)
+			if test.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+					t.Fatalf("Download() = %v, want error containing %q", err, test.wantErr)
+				}
+				if tmpDir != "" {
+					t.Errorf("Download() failed but returned temp dir %s", tmpDir)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Download() = %v", err)
+			}
+			defer os.RemoveAll(tmpDir)
+
+			if content, err := os.ReadFile(binaryPath); err != nil || string(content) != "new gvm" {
+				t.Errorf("downloaded binary = %q, %v", content, err)
+			}
+			if signed != test.wantSigned {
+				t.Errorf("Download() signed = %v, want %v", signed, test.wantSigned)
+			}
+		})
+	}
+}
+
+func TestReplaceFile(t *testing.T) {
+	dir := t.TempDir()
+	executable := filepath.Join(dir, "gvm")
+	if err := os.WriteFile(executable, []byte("old gvm"), 0750); err != nil {
+		t.Fatal(err)
+	}
+	newBinary := filepath.Join(dir, "download", "gvm")
+	if err := os.MkdirAll(filepath.Dir(newBinary), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(newBinary, []byte("new gvm"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := replaceFile(executable, newBinary); err != nil {
+		t.Fatalf("replaceFile() = %v", err)
+	}
+
+	if content, err := os.ReadFile(executable); err != nil || string(content) != "new gvm" {
+		t.Errorf("executable = %q, %v", content, err)
+	}
+	if runtime.GOOS != "windows" {
+		if info, err := os.Stat(executable); err != nil || info.Mode().Perm() != 0751 {
+			t.Errorf("executable mode = %v, %v, want 0751", info.Mode().Perm(), err)
+		}
+	}
+	if _, err := os.Stat(executable + ".new"); !os.IsNotExist(err) {
+		t.Errorf("staged binary left behind: %v", err)
+	}
+
+	if err := replaceFile(filepath.Join(dir, "missing"), newBinary); err == nil {
+		t.Error("replaceFile() of a missing executable succeeded")
+	}
+}
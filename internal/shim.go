@@ -0,0 +1,486 @@
+package internal
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+const (
+	// Environment variable selecting the go version for the current
+	// process tree, e.g. set by `gvm shell`.
+	VersionEnvVar = "GVM_GO_VERSION"
+	// Per project file pinning the go version, e.g. "1.25.5".
+	GoVersionFile = ".go-version"
+	// Source of a version request falling back to the default version.
+	DefaultVersionSource = "default"
+
+	gvmHomeDirName = ".gvm"
+	shimsDirName   = "shims"
+	shimIndexFile  = "index"
+	shimMarker     = "gvm shim"
+)
+
+// Directory holding the shims and the shim index, ~/.gvm.
+func GvmHomeDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(home, gvmHomeDirName), nil
+}
+
+// Directory holding the go/gofmt shims, ~/.gvm/shims. Put it first in
+// PATH to select the go version per directory.
+func ShimsDir() (string, error) {
+	gvmHome, err := GvmHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(gvmHome, shimsDirName), nil
+}
+
+// A requested go version and where the request came from: the
+// environment variable, the path of a .go-version or go.mod file or
+// "default".
+type VersionRequest struct {
+	Version string `json:"version"`
+	Source  string `json:"source"`
+}
+
+// Whether the version was requested by the project in the directory
+// (.go-version or go.mod) rather than the environment or the default.
+func (r *VersionRequest) IsProject() bool {
+	return r.Source != VersionEnvVar && r.Source != DefaultVersionSource
+}
+
+// Determines the go version requested for dir, in order of precedence
+// from the GVM_GO_VERSION environment variable, the nearest .go-version
+// file, the nearest go.mod (its toolchain directive before its go
+// directive) and the default version. Returns nil when none is set.
+func ResolveVersionRequest(dir string, defaultVersion string) *VersionRequest {
+	if version := strings.TrimSpace(os.Getenv(VersionEnvVar)); version != "" {
+		return &VersionRequest{Version: version, Source: VersionEnvVar}
+	}
+
+	if request := ResolveProjectVersionRequest(dir); request != nil {
+		return request
+	}
+
+	if defaultVersion != "" {
+		return &VersionRequest{Version: defaultVersion, Source: DefaultVersionSource}
+	}
+
+	return nil
+}
+
+// Determines the go version pinned by the project in dir, from the
+// nearest .go-version file or go.mod. Returns nil when none is pinned.
+func ResolveProjectVersionRequest(dir string) *VersionRequest {
+	if path, ok := findUp(dir, GoVersionFile); ok {
+		if version := readGoVersionFile(path); version != "" {
+			return &VersionRequest{Version: version, Source: path}
+		}
+	}
+
+	if path, ok := findUp(dir, "go.mod"); ok {
+		if version := ReadGoModVersion(path); version != "" {
+			return &VersionRequest{Version: version, Source: path}
+		}
+	}
+
+	return nil
+}
+
+// Reports whether dir or one of its parents holds a go.mod or
+// .go-version file.
+func IsGoProjectDir(dir string) bool {
+	if _, ok := findUp(dir, GoVersionFile); ok {
+		return true
+	}
+	_, ok := findUp(dir, "go.mod")
+	return ok
+}
+
+// Returns the path of the nearest file called name in dir or one of its
+// parents.
+func findUp(dir string, name string) (string, bool) {
+	for {
+		path := filepath.Join(dir, name)
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path, true
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", false
+		}
+		dir = parent
+	}
+}
+
+// Reads the version of a .go-version file, its first non comment line.
+func readGoVersionFile(path string) string {
+	file, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line != "" && !strings.HasPrefix(line, "#") {
+			return line
+		}
+	}
+	return ""
+}
+
+// Reads the go version a go.mod file asks for. The toolchain directive
+// (e.g. "toolchain go1.25.5") is preferred over the go directive
+// (e.g. "go 1.25"). Returns an empty string when neither is present.
+func ReadGoModVersion(path string) string {
+	goVersion, toolchain := readGoModDirectives(path)
+	if toolchain != "" {
+		return toolchain
+	}
+	return goVersion
+}
+
+// Reads the go and toolchain directives of a go.mod file without
+// parsing the complete file.
+func readGoModDirectives(path string) (goVersion string, toolchain string) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", ""
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "//")
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+
+		switch fields[0] {
+		case "go":
+			goVersion = fields[1]
+		case "toolchain":
+			if fields[1] != "default" {
+				toolchain = fields[1]
+			}
+		}
+	}
+
+	return goVersion, toolchain
+}
+
+// Downloaded toolchains and the default version as needed by the shims,
+// kept next to them in ~/.gvm/index so a shim never has to parse the
+// complete config.
+type ShimIndex struct {
+	Default string
+	// version name -> GOROOT
+	Toolchains map[string]string
+}
+
+func shimIndexPath() (string, error) {
+	gvmHome, err := GvmHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(gvmHome, shimIndexFile), nil
+}
+
+// Builds the shim index of the config. Tarball toolchains point at the
+// directory GoRoot extracts them into, which may not exist yet.
+func (c *Config) ShimIndex() *ShimIndex {
+	index := &ShimIndex{Default: c.DefaultVersion, Toolchains: make(map[string]string)}
+
+	for name, downloadVersion := range c.DownloadedVersions {
+		switch {
+		case downloadVersion.IsLink():
+			index.Toolchains[name] = downloadVersion.LinkPath
+		case c.DownloadPath != "":
+			index.Toolchains[name] = filepath.Join(c.DownloadPath, ToolchainsDir, downloadVersion.Version, "go")
+		}
+	}
+
+	return index
+}
+
+// Writes the shim index of the config to ~/.gvm/index.
+func (c *Config) WriteShimIndex() error {
+	indexPath, err := shimIndexPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(indexPath), 0755); err != nil {
+		return fmt.Errorf("Shim Error: %w", err)
+	}
+
+	index := c.ShimIndex()
+	names := make([]string, 0, len(index.Toolchains))
+	for name := range index.Toolchains {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var builder strings.Builder
+	builder.WriteString("# generated by gvm from config.json, do not edit\n")
+	if index.Default != "" {
+		fmt.Fprintf(&builder, "default\t%s\n", index.Default)
+	}
+	for _, name := range names {
+		fmt.Fprintf(&builder, "toolchain\t%s\t%s\n", name, index.Toolchains[name])
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(indexPath), ".index-")
+	if err != nil {
+		return fmt.Errorf("Shim Error: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.WriteString(builder.String()); err != nil {
+		tmp.Close()
+		return fmt.Errorf("Shim Error: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("Shim Error: %w", err)
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return fmt.Errorf("Shim Error: %w", err)
+	}
+
+	return os.Rename(tmp.Name(), indexPath)
+}
+
+// Reads the shim index. When it doesn't exist yet (e.g. gvm was set up
+// by an older release) it is built from the config once.
+func LoadShimIndex() (*ShimIndex, error) {
+	indexPath, err := shimIndexPath()
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(indexPath)
+	if os.IsNotExist(err) {
+		gvmConfig, err := LoadConfig()
+		if err != nil {
+			return nil, err
+		}
+		gvmConfig.WriteShimIndex()
+		return gvmConfig.ShimIndex(), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Shim Error: %w", err)
+	}
+	defer file.Close()
+
+	index := &ShimIndex{Toolchains: make(map[string]string)}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Split(scanner.Text(), "\t")
+		switch {
+		case fields[0] == "default" && len(fields) == 2:
+			index.Default = fields[1]
+		case fields[0] == "toolchain" && len(fields) == 3:
+			index.Toolchains[fields[1]] = fields[2]
+		}
+	}
+
+	return index, scanner.Err()
+}
+
+// Finds the downloaded toolchain satisfying a requested version and
+// returns its name and GOROOT, see SelectVersion.
+func (index *ShimIndex) Resolve(request *VersionRequest) (string, string, bool) {
+	names := make([]string, 0, len(index.Toolchains))
+	for name := range index.Toolchains {
+		names = append(names, name)
+	}
+
+	name, ok := SelectVersion(request, names)
+	return name, index.Toolchains[name], ok
+}
+
+// Picks the version satisfying a request among names. An exact match
+// wins for requests naming a patch or prerelease (e.g. "1.25.5" or
+// "1.26rc1") and for names which aren't versions. Requests without a
+// patch number (e.g. "1.25" from .go-version or "go 1.25" in go.mod) and
+// requests from go.mod, which names a minimum version, fall back to the
+// newest stable release of the same minor line that is at least as new
+// as the request, so "1.25" selects go1.25.5 rather than go1.25.0.
+func SelectVersion(request *VersionRequest, names []string) (string, bool) {
+	requested, err := ParseGoVersion(request.Version)
+	if err != nil || requested.HasPatch() || requested.IsPrerelease() {
+		for _, name := range names {
+			if SameGoVersion(name, request.Version) {
+				return name, true
+			}
+		}
+	}
+
+	if err != nil || (requested.HasPatch() && filepath.Base(request.Source) != "go.mod") {
+		return "", false
+	}
+
+	bestName := ""
+	var best GoVersion
+	for _, name := range names {
+		candidate, ok := parseReleaseVersion(name)
+		if !ok || !candidate.SameMinor(requested) || candidate.Less(requested) {
+			continue
+		}
+		if bestName == "" || best.Less(candidate) {
+			bestName, best = name, candidate
+		}
+	}
+
+	return bestName, bestName != ""
+}
+
+// Names of the tools a shim is generated for: go and gofmt plus every
+// binary in the bin directory of the downloaded toolchains.
+func (index *ShimIndex) ToolNames() []string {
+	tools := map[string]bool{"go": true, "gofmt": true}
+
+	for _, goroot := range index.Toolchains {
+		entries, err := os.ReadDir(filepath.Join(goroot, "bin"))
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			if !entry.IsDir() {
+				tools[strings.TrimSuffix(entry.Name(), ".exe")] = true
+			}
+		}
+	}
+
+	names := make([]string, 0, len(tools))
+	for name := range tools {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Regenerates the shims in ~/.gvm/shims for the tools of the index and
+// removes shims of tools no toolchain provides anymore. The shims run
+// gvmExecutable, which resolves the version and executes the tool.
+// Returns the names of the shims.
+func RehashShims(index *ShimIndex, gvmExecutable string) ([]string, error) {
+	shimsDir, err := ShimsDir()
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(shimsDir, 0755); err != nil {
+		return nil, fmt.Errorf("Shim Error: %w", err)
+	}
+
+	tools := index.ToolNames()
+	wanted := make(map[string]bool)
+
+	for _, tool := range tools {
+		name, content := shimScript(tool, gvmExecutable)
+		wanted[name] = true
+
+		path := filepath.Join(shimsDir, name)
+		if err := os.WriteFile(path+".tmp", []byte(content), 0755); err != nil {
+			return nil, fmt.Errorf("Shim Error: %w", err)
+		}
+		if err := os.Rename(path+".tmp", path); err != nil {
+			os.Remove(path + ".tmp")
+			return nil, fmt.Errorf("Shim Error: %w", err)
+		}
+	}
+
+	entries, err := os.ReadDir(shimsDir)
+	if err != nil {
+		return nil, fmt.Errorf("Shim Error: %w", err)
+	}
+	for _, entry := range entries {
+		if wanted[entry.Name()] || entry.IsDir() {
+			continue
+		}
+		// only remove files generated by gvm
+		path := filepath.Join(shimsDir, entry.Name())
+		if data, err := os.ReadFile(path); err == nil && strings.Contains(string(data), shimMarker) {
+			os.Remove(path)
+		}
+	}
+
+	return tools, nil
+}
+
+// Returns the file name and content of the shim of a tool.
+func shimScript(tool string, gvmExecutable string) (string, string) {
+	if runtime.GOOS == "windows" {
+		return tool + ".cmd", fmt.Sprintf("@echo off\r\nrem %s, regenerate with `gvm rehash`\r\n\"%s\" shim-exec %s %%*\r\n", shimMarker, gvmExecutable, tool)
+	}
+
+	return tool, fmt.Sprintf("#!/bin/sh\n# %s, regenerate with `gvm rehash`\nexec '%s' shim-exec %s \"$@\"\n", shimMarker, strings.ReplaceAll(gvmExecutable, "'", `'\''`), tool)
+}
+
+// Makes sure the toolchain of an index entry exists, extracting the
+// tarball of a downloaded version on first use, which needs the full
+// config. Returns its GOROOT.
+func PrepareGoRoot(name string, goroot string) (string, error) {
+	if _, err := os.Stat(filepath.Join(goroot, "bin")); err == nil {
+		return goroot, nil
+	}
+
+	gvmConfig, err := LoadConfig()
+	if err != nil {
+		return "", err
+	}
+
+	downloadVersion := gvmConfig.ResolveDownloadedVersion(name)
+	if downloadVersion == nil {
+		return "", fmt.Errorf("Shim Error: Go %s is not installed anymore. Run 'gvm rehash'", name)
+	}
+
+	return downloadVersion.GoRoot()
+}
+
+// Runs a tool of the toolchain at goroot with the given arguments and
+// the standard streams of gvm, returning its exit code. Interrupts are
+// left to the tool, which receives them from the terminal as well.
+func RunToolchainTool(goroot string, tool string, args []string) (int, error) {
+	toolPath := filepath.Join(goroot, "bin", tool)
+	if runtime.GOOS == "windows" {
+		toolPath += ".exe"
+	}
+	if _, err := os.Stat(toolPath); err != nil {
+		return 0, fmt.Errorf("Shim Error: %s not found in %s", tool, goroot)
+	}
+
+	command := exec.Command(toolPath, args...)
+	command.Env = ToolchainEnv(goroot, os.Environ())
+	command.Stdin = os.Stdin
+	command.Stdout = os.Stdout
+	command.Stderr = os.Stderr
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt)
+	defer signal.Stop(signals)
+
+	err := command.Run()
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		return exitErr.ExitCode(), nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("Shim Error: %w", err)
+	}
+
+	return 0, nil
+}
@@ -0,0 +1,36 @@
+package internal
+
+import "testing"
+
+func TestSelectVersion(t *testing.T) {
+	names := []string{"go1.24.2", "go1.25.0", "go1.25.5", "go1.25.3", "go1.26rc1", "system-1.22"}
+
+	tests := []struct {
+		version string
+		source  string
+		want    string
+		wantOK  bool
+	}{
+		{version: "1.25", source: ".go-version", want: "go1.25.5", wantOK: true},
+		{version: "go1.25", source: ".go-version", want: "go1.25.5", wantOK: true},
+		{version: "1.25", source: "go.mod", want: "go1.25.5", wantOK: true},
+		{version: "1.25.0", source: ".go-version", want: "go1.25.0", wantOK: true},
+		{version: "go1.25.3", source: ".go-version", want: "go1.25.3", wantOK: true},
+		{version: "1.25.4", source: ".go-version", wantOK: false},
+		{version: "1.25.4", source: "go.mod", want: "go1.25.5", wantOK: true},
+		{version: "1.26rc1", source: ".go-version", want: "go1.26rc1", wantOK: true},
+		{version: "1.26", source: ".go-version", wantOK: false},
+		{version: "1.24", source: "/src/project/go.mod", want: "go1.24.2", wantOK: true},
+		{version: "system-1.22", source: ".go-version", want: "system-1.22", wantOK: true},
+		{version: "1.23", source: ".go-version", wantOK: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.version+"@"+test.source, func(t *testing.T) {
+			got, ok := SelectVersion(&VersionRequest{Version: test.version, Source: test.source}, names)
+			if got != test.want || ok != test.wantOK {
+				t.Errorf("SelectVersion(%s) = %q, %v, want %q, %v", test.version, got, ok, test.want, test.wantOK)
+			}
+		})
+	}
+}
@@ -0,0 +1,312 @@
+package internal
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"golang.org/x/crypto/blake2b"
+)
+
+// Extensions of detached signatures looked up next to an artifact.
+const (
+	MinisignSignatureExt = ".minisig"
+	SignatureExt         = ".sig"
+)
+
+// Returned when no detached signature exists for an artifact.
+var ErrUnsigned = errors.New("artifact is not signed")
+
+// A public key able to verify a detached signature of a file.
+type signatureKey interface {
+	// extension of the signatures made with this key
+	signatureExt() string
+	verify(artifactPath string, signature []byte) error
+}
+
+// Public keys trusted to sign toolchain archives.
+type Keyring struct {
+	keys []signatureKey
+}
+
+// Loads a keyring from public key files. minisign public keys and PEM
+// encoded ECDSA, Ed25519 and RSA keys (as used by cosign) are supported.
+func LoadKeyring(paths []string) (*Keyring, error) {
+	keyring := &Keyring{}
+
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("Signature Error: failed to read key %s: %w", path, err)
+		}
+
+		var key signatureKey
+		if block, _ := pem.Decode(data); block != nil {
+			key, err = parsePEMKey(block)
+		} else {
+			key, err = parseMinisignKey(data)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("Signature Error: %s: %w", path, err)
+		}
+
+		keyring.keys = append(keyring.keys, key)
+	}
+
+	return keyring, nil
+}
+
+// Reports whether the keyring holds any key.
+func (k *Keyring) IsEmpty() bool {
+	return len(k.keys) == 0
+}
+
+// Verifies the artifact against the detached signatures found by
+// loadSignature (e.g. next to it on disk or at its download url).
+// Succeeds when any key of the keyring verifies its signature, returns
+// ErrUnsigned when no signature exists at all.
+func (k *Keyring) Verify(artifactPath string, loadSignature func(ext string) ([]byte, error)) error {
+	signatures := make(map[string][]byte)
+	var lastErr error
+
+	for _, key := range k.keys {
+		ext := key.signatureExt()
+
+		signature, loaded := signatures[ext]
+		if !loaded {
+			var err error
+			if signature, err = loadSignature(ext); err != nil && !errors.Is(err, ErrUnsigned) {
+				return err
+			}
+			signatures[ext] = signature
+		}
+
+		if signature == nil {
+			continue
+		}
+
+		if lastErr = key.verify(artifactPath, signature); lastErr == nil {
+			return nil
+		}
+	}
+
+	if lastErr != nil {
+		return fmt.Errorf("Signature Error: %s: %w", artifactPath, lastErr)
+	}
+	return ErrUnsigned
+}
+
+// Loads signatures stored next to a local file, e.g. <file>.minisig.
+func LocalSignatureLoader(path string) func(ext string) ([]byte, error) {
+	return func(ext string) ([]byte, error) {
+		data, err := os.ReadFile(path + ext)
+		if os.IsNotExist(err) {
+			return nil, ErrUnsigned
+		}
+		return data, err
+	}
+}
+
+// Loads signatures published next to a download, e.g. <url>.minisig.
+func RemoteSignatureLoader(url string) func(ext string) ([]byte, error) {
+	return func(ext string) ([]byte, error) {
+		response, err := HTTPClient().Get(url + ext)
+		if err != nil {
+			return nil, fmt.Errorf("Signature Error: %w", err)
+		}
+		defer response.Body.Close()
+
+		if response.StatusCode == http.StatusNotFound {
+			return nil, ErrUnsigned
+		}
+		if response.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("Signature Error: failed to fetch %s%s. Status: %s", url, ext, response.Status)
+		}
+
+		return io.ReadAll(io.LimitReader(response.Body, 64*1024))
+	}
+}
+
+// Verifies the signature of a downloaded artifact according to the
+// signature settings of the config. Unsigned artifacts are accepted
+// unless requireSignature or the config demand a signature.
+// Returns whether a signature was verified.
+func (c *Config) VerifySignature(artifactPath string, loadSignature func(ext string) ([]byte, error), requireSignature bool) (bool, error) {
+	requireSignature = requireSignature || c.RequireSignature
+
+	keyring, err := LoadKeyring(c.SignatureKeys)
+	if err != nil {
+		return false, err
+	}
+
+	if keyring.IsEmpty() {
+		if requireSignature {
+			return false, fmt.Errorf("Signature Error: signatures are required but no signature_keys are configured")
+		}
+		return false, nil
+	}
+
+	err = keyring.Verify(artifactPath, loadSignature)
+	if errors.Is(err, ErrUnsigned) {
+		if requireSignature {
+			return false, fmt.Errorf("Signature Error: %s is not signed and signatures are required", artifactPath)
+		}
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// minisign public key, see https://jedisct1.github.io/minisign
+type minisignKey struct {
+	keyID     [8]byte
+	publicKey ed25519.PublicKey
+}
+
+// Decodes the base64 line of a minisign key or signature file,
+// skipping the comment lines.
+func minisignLines(data []byte) []string {
+	lines := make([]string, 0)
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+func parseMinisignKey(data []byte) (signatureKey, error) {
+	var encoded string
+	for _, line := range minisignLines(data) {
+		if !strings.HasPrefix(line, "untrusted comment:") {
+			encoded = line
+			break
+		}
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil || len(raw) != 42 || string(raw[:2]) != "Ed" {
+		return nil, fmt.Errorf("not a minisign public key or PEM encoded public key")
+	}
+
+	key := &minisignKey{publicKey: ed25519.PublicKey(raw[10:42])}
+	copy(key.keyID[:], raw[2:10])
+	return key, nil
+}
+
+func (k *minisignKey) signatureExt() string {
+	return MinisignSignatureExt
+}
+
+func (k *minisignKey) verify(artifactPath string, signature []byte) error {
+	lines := minisignLines(signature)
+	if len(lines) < 4 || !strings.HasPrefix(lines[0], "untrusted comment:") || !strings.HasPrefix(lines[2], "trusted comment: ") {
+		return fmt.Errorf("malformed minisign signature")
+	}
+
+	sig, err := base64.StdEncoding.DecodeString(lines[1])
+	if err != nil || len(sig) != 74 {
+		return fmt.Errorf("malformed minisign signature")
+	}
+	if !bytes.Equal(sig[2:10], k.keyID[:]) {
+		return fmt.Errorf("signed with a different minisign key")
+	}
+
+	content, err := os.ReadFile(artifactPath)
+	if err != nil {
+		return err
+	}
+
+	switch string(sig[:2]) {
+	case "Ed":
+		// legacy signature over the whole file
+	case "ED":
+		digest := blake2b.Sum512(content)
+		content = digest[:]
+	default:
+		return fmt.Errorf("unsupported minisign algorithm %q", sig[:2])
+	}
+
+	if !ed25519.Verify(k.publicKey, content, sig[10:]) {
+		return fmt.Errorf("invalid minisign signature")
+	}
+
+	globalSig, err := base64.StdEncoding.DecodeString(lines[3])
+	if err != nil || len(globalSig) != ed25519.SignatureSize {
+		return fmt.Errorf("malformed minisign trusted comment signature")
+	}
+	trustedComment := strings.TrimPrefix(lines[2], "trusted comment: ")
+	if !ed25519.Verify(k.publicKey, append(sig[10:], []byte(trustedComment)...), globalSig) {
+		return fmt.Errorf("invalid minisign trusted comment signature")
+	}
+
+	return nil
+}
+
+// PEM encoded public key as used by cosign style signing. Signatures
+// are base64 (or raw) over the sha256 of the artifact for ECDSA and RSA
+// and over the artifact itself for Ed25519.
+type pemKey struct {
+	publicKey crypto.PublicKey
+}
+
+func parsePEMKey(block *pem.Block) (signatureKey, error) {
+	publicKey, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid PEM public key: %w", err)
+	}
+
+	switch publicKey.(type) {
+	case *ecdsa.PublicKey, ed25519.PublicKey, *rsa.PublicKey:
+		return &pemKey{publicKey: publicKey}, nil
+	default:
+		return nil, fmt.Errorf("unsupported public key type %T", publicKey)
+	}
+}
+
+func (k *pemKey) signatureExt() string {
+	return SignatureExt
+}
+
+func (k *pemKey) verify(artifactPath string, signature []byte) error {
+	if decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(signature))); err == nil {
+		signature = decoded
+	}
+
+	content, err := os.ReadFile(artifactPath)
+	if err != nil {
+		return err
+	}
+	digest := sha256.Sum256(content)
+
+	valid := false
+	switch publicKey := k.publicKey.(type) {
+	case *ecdsa.PublicKey:
+		valid = ecdsa.VerifyASN1(publicKey, digest[:], signature)
+	case ed25519.PublicKey:
+		valid = ed25519.Verify(publicKey, content, signature)
+	case *rsa.PublicKey:
+		valid = rsa.VerifyPKCS1v15(publicKey, crypto.SHA256, digest[:], signature) == nil
+	}
+
+	if !valid {
+		return fmt.Errorf("invalid signature")
+	}
+	return nil
+}
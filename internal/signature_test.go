@@ -0,0 +1,170 @@
+package internal
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Fixtures in testdata/signature were made with minisign and openssl:
+// artifact.tar.gz.minisig is a prehashed ("ED") signature, legacy.minisig
+// a legacy ("Ed") one and <type>.sig signatures of the PEM keys.
+var signatureTestdata = filepath.Join("testdata", "signature")
+
+func signatureFixture(name string) string {
+	return filepath.Join(signatureTestdata, name)
+}
+
+func readSignatureFixture(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(signatureFixture(name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+// Loads the given signature for any extension.
+func staticSignatureLoader(signature []byte) func(ext string) ([]byte, error) {
+	return func(ext string) ([]byte, error) {
+		if signature == nil {
+			return nil, ErrUnsigned
+		}
+		return signature, nil
+	}
+}
+
+func TestKeyringVerify(t *testing.T) {
+	minisig := string(readSignatureFixture(t, "artifact.tar.gz.minisig"))
+	lines := strings.Split(minisig, "\n")
+	lines[2] = strings.Replace(lines[2], "hashed", "tampered", 1)
+	tampered := strings.Join(lines, "\n")
+
+	tests := []struct {
+		name      string
+		key       string
+		artifact  string
+		signature []byte
+		wantErr   string
+	}{
+		{name: "minisign prehashed", key: "minisign.pub", signature: []byte(minisig)},
+		{name: "minisign legacy", key: "minisign.pub", signature: readSignatureFixture(t, "legacy.minisig")},
+		{name: "minisign wrong key id", key: "other.pub", signature: []byte(minisig), wantErr: "signed with a different minisign key"},
+		{name: "minisign tampered trusted comment", key: "minisign.pub", signature: []byte(tampered), wantErr: "invalid minisign trusted comment signature"},
+		{name: "minisign tampered artifact", key: "minisign.pub", artifact: "legacy.minisig", signature: []byte(minisig), wantErr: "invalid minisign signature"},
+		{name: "minisign malformed", key: "minisign.pub", signature: []byte("untrusted comment: x\nnot base64\n"), wantErr: "malformed minisign signature"},
+		{name: "minisign truncated", key: "minisign.pub", signature: []byte(strings.Join(lines[:2], "\n")), wantErr: "malformed minisign"},
+		{name: "ecdsa base64", key: "ecdsa.pem", signature: readSignatureFixture(t, "ecdsa.sig")},
+		{name: "ed25519 raw", key: "ed25519.pem", signature: readSignatureFixture(t, "ed25519.sig")},
+		{name: "rsa base64", key: "rsa.pem", signature: readSignatureFixture(t, "rsa.sig")},
+		{name: "ecdsa with rsa signature", key: "ecdsa.pem", signature: readSignatureFixture(t, "rsa.sig"), wantErr: "invalid signature"},
+		{name: "rsa tampered artifact", key: "rsa.pem", artifact: "legacy.minisig", signature: readSignatureFixture(t, "rsa.sig"), wantErr: "invalid signature"},
+		{name: "unsigned", key: "minisign.pub", wantErr: ErrUnsigned.Error()},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			keyring, err := LoadKeyring([]string{signatureFixture(test.key)})
+			if err != nil {
+				t.Fatal(err)
+			}
+			artifact := "artifact.tar.gz"
+			if test.artifact != "" {
+				artifact = test.artifact
+			}
+
+			err = keyring.Verify(signatureFixture(artifact), staticSignatureLoader(test.signature))
+			if test.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Verify() = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+				t.Fatalf("Verify() = %v, want error containing %q", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestKeyringVerifyAnyKey(t *testing.T) {
+	keyring, err := LoadKeyring([]string{signatureFixture("other.pub"), signatureFixture("rsa.pem"), signatureFixture("minisign.pub")})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// only the minisign signature exists, other.pub fails on it first
+	err = keyring.Verify(signatureFixture("artifact.tar.gz"), LocalSignatureLoader(signatureFixture("artifact.tar.gz")))
+	if err != nil {
+		t.Fatalf("Verify() = %v", err)
+	}
+}
+
+func TestLoadKeyringErrors(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"garbage.pub":   "not a key\n",
+		"short.pub":     "untrusted comment: x\nRWQAAAA=\n",
+		"cert.pem":      "-----BEGIN PUBLIC KEY-----\nAAAA\n-----END PUBLIC KEY-----\n",
+		"wrong-alg.pub": "untrusted comment: x\nRUTDO0EOwaV3mz5adMrl9BbJgsv65wbzoXCDseJDnHHft9Ri2H6McLnt\n",
+	}
+
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadKeyring([]string{path}); err == nil || !strings.HasPrefix(err.Error(), "Signature Error:") {
+			t.Errorf("LoadKeyring(%s) = %v, want Signature Error", name, err)
+		}
+	}
+
+	if _, err := LoadKeyring([]string{filepath.Join(dir, "missing.pub")}); err == nil {
+		t.Error("LoadKeyring() of a missing file succeeded")
+	}
+}
+
+func TestVerifySignaturePolicy(t *testing.T) {
+	artifact := signatureFixture("artifact.tar.gz")
+	signed := LocalSignatureLoader(artifact)
+	unsigned := staticSignatureLoader(nil)
+	keys := []string{signatureFixture("minisign.pub")}
+
+	tests := []struct {
+		name          string
+		keys          []string
+		configRequire bool
+		require       bool
+		loader        func(ext string) ([]byte, error)
+		wantSigned    bool
+		wantErr       bool
+	}{
+		{name: "no keys, unsigned", loader: unsigned},
+		{name: "no keys, signed", loader: signed},
+		{name: "no keys, required", require: true, loader: signed, wantErr: true},
+		{name: "no keys, required by config", configRequire: true, loader: signed, wantErr: true},
+		{name: "keys, unsigned", keys: keys, loader: unsigned},
+		{name: "keys, unsigned, required", keys: keys, require: true, loader: unsigned, wantErr: true},
+		{name: "keys, unsigned, required by config", keys: keys, configRequire: true, loader: unsigned, wantErr: true},
+		{name: "keys, signed", keys: keys, loader: signed, wantSigned: true},
+		{name: "keys, signed, required", keys: keys, require: true, loader: signed, wantSigned: true},
+		{name: "keys, bad signature", keys: []string{signatureFixture("other.pub")}, loader: signed, wantErr: true},
+		{name: "keys, loader fails", keys: keys, loader: func(string) ([]byte, error) { return nil, errors.New("offline") }, wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			config := &Config{SignatureKeys: test.keys, RequireSignature: test.configRequire}
+
+			gotSigned, err := config.VerifySignature(artifact, test.loader, test.require)
+			if (err != nil) != test.wantErr {
+				t.Fatalf("VerifySignature() = %v, wantErr %v", err, test.wantErr)
+			}
+			if gotSigned != test.wantSigned {
+				t.Errorf("VerifySignature() signed = %v, want %v", gotSigned, test.wantSigned)
+			}
+		})
+	}
+}
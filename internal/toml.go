@@ -0,0 +1,70 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// Parses a TOML document like gvm.toml or gvm.lock into nested maps:
+// tables are map[string]any, arrays of tables []map[string]any, arrays
+// []any and integers int64.
+func ParseTOML(data []byte) (map[string]any, error) {
+	document := make(map[string]any)
+
+	if _, err := toml.Decode(string(data), &document); err != nil {
+		var parseErr toml.ParseError
+		if errors.As(err, &parseErr) {
+			return nil, fmt.Errorf("TOML Error: line %d: %s", parseErr.Position.Line, parseErr.Message)
+		}
+		return nil, fmt.Errorf("TOML Error: %w", err)
+	}
+
+	return document, nil
+}
+
+// Quotes a string as a TOML basic string.
+func tomlQuote(s string) string {
+	var builder strings.Builder
+	builder.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"', '\\':
+			builder.WriteRune('\\')
+			builder.WriteRune(r)
+		case '\n':
+			builder.WriteString(`\n`)
+		case '\t':
+			builder.WriteString(`\t`)
+		case '\r':
+			builder.WriteString(`\r`)
+		default:
+			if r < 0x20 || r == 0x7f {
+				fmt.Fprintf(&builder, `\u%04X`, r)
+			} else {
+				builder.WriteRune(r)
+			}
+		}
+	}
+	builder.WriteByte('"')
+	return builder.String()
+}
+
+// Formats a key for TOML, quoting it unless it's a bare key.
+func tomlKey(key string) string {
+	for _, r := range key {
+		if !isBareKeyRune(r) {
+			return tomlQuote(key)
+		}
+	}
+	if key == "" {
+		return `""`
+	}
+	return key
+}
+
+func isBareKeyRune(r rune) bool {
+	return r == '_' || r == '-' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9')
+}
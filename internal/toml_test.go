@@ -0,0 +1,43 @@
+package internal
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseTOMLErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr string
+	}{
+		{name: "duplicate key", input: "a = 1\na = 2\n", wantErr: "line 2:"},
+		{name: "duplicate table", input: "[a]\nx = 1\n\n[a]\ny = 2\n", wantErr: "line 4:"},
+		{name: "unterminated string", input: "a = \"abc\n", wantErr: "line 1:"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := ParseTOML([]byte(test.input))
+			if err == nil {
+				t.Fatalf("ParseTOML() = %#v, want error", got)
+			}
+			if !strings.HasPrefix(err.Error(), "TOML Error: "+test.wantErr) {
+				t.Errorf("ParseTOML() = %v, want error starting with %q", err, "TOML Error: "+test.wantErr)
+			}
+		})
+	}
+}
+
+func TestTOMLQuoteRoundTrip(t *testing.T) {
+	for _, value := range []string{"", "plain", `quo"te`, `back\slash`, "tab\tnew\nline\r", "\x01ctrl\x7f", "ünïcode", "linux/amd64"} {
+		key := tomlKey(value)
+		document, err := ParseTOML([]byte(key + " = " + tomlQuote(value) + "\n"))
+		if err != nil {
+			t.Fatalf("ParseTOML(%s) = %v", key, err)
+		}
+		if document[value] != value {
+			t.Errorf("round trip of %q = %#v", value, document)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package internal
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Directory inside the download directory holding extracted toolchains
+// which are used directly (bootstrap, exec, build) instead of through
+// the global /usr/local/go installation.
+const ToolchainsDir = ".toolchains"
+
+// Looks up a downloaded version by its exact name (e.g. "go1.25.5" or a
+// linked name) or by any other spelling of the same version, e.g. "1.25.5".
+// Returns nil when the version is not downloaded.
+func (c *Config) ResolveDownloadedVersion(requested string) *DownloadVersion {
+	requested = strings.TrimSpace(requested)
+
+	if downloadedVersion, ok := c.DownloadedVersions[requested]; ok {
+		return &downloadedVersion
+	}
+
+	for _, downloadedVersion := range c.DownloadedVersions {
+		if SameGoVersion(downloadedVersion.Version, requested) {
+			return &downloadedVersion
+		}
+	}
+
+	return nil
+}
+
+// Looks up a version of the remote index by any spelling of its name,
+// e.g. "1.25.5" or "go1.25.5". Returns nil when it isn't available.
+func (c *Config) FindAvailableVersion(requested string) *RemoteVersion {
+	return findRemoteVersion(c.AvailableVersions, requested)
+}
+
+func findRemoteVersion(versions []RemoteVersion, requested string) *RemoteVersion {
+	for _, remoteVersion := range versions {
+		if SameGoVersion(remoteVersion.Version, requested) {
+			return &remoteVersion
+		}
+	}
+	return nil
+}
+
+// Returns a GOROOT for the version which can be used without switching
+// the global installation. Linked versions return their own directory,
+// tarballs are extracted once into the toolchains cache and reused.
+func (dv *DownloadVersion) GoRoot() (string, error) {
+	if dv.IsLink() {
+		return dv.LinkPath, nil
+	}
+
+	toolchainDir, err := dv.ToolchainDir()
+	if err != nil {
+		return "", err
+	}
+
+	goroot := filepath.Join(toolchainDir, "go")
+
+	if _, err := os.Stat(filepath.Join(goroot, "bin", "go")); err == nil {
+		return goroot, nil
+	}
+
+	if err := os.RemoveAll(toolchainDir); err != nil {
+		return "", fmt.Errorf("Toolchain Error: failed to clean %s: %w", toolchainDir, err)
+	}
+
+	if err := ExtractArchive(dv.TarPath, toolchainDir); err != nil {
+		return "", err
+	}
+
+	return goroot, nil
+}
+
+// Returns the directory the version's tarball is extracted into by GoRoot.
+func (dv *DownloadVersion) ToolchainDir() (string, error) {
+	downloadDirPath, err := GoDownloadDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(*downloadDirPath, ToolchainsDir, dv.Version), nil
+}
+
+// Builds an environment running goroot's toolchain in isolation from
+// whatever go is installed globally: GOROOT points at goroot, its bin
+// directory comes first in PATH and GOTOOLCHAIN=local prevents the go
+// command from switching to another toolchain on its own.
+func ToolchainEnv(goroot string, base []string) []string {
+	env := make([]string, 0, len(base)+3)
+	path := ""
+
+	for _, entry := range base {
+		key, value, _ := strings.Cut(entry, "=")
+		switch key {
+		case "GOROOT", "GOTOOLCHAIN":
+			continue
+		case "PATH":
+			path = value
+			continue
+		}
+		env = append(env, entry)
+	}
+
+	goBin := filepath.Join(goroot, "bin")
+	if path != "" {
+		path = goBin + string(os.PathListSeparator) + path
+	} else {
+		path = goBin
+	}
+
+	return append(env,
+		fmt.Sprintf("GOROOT=%s", goroot),
+		fmt.Sprintf("PATH=%s", path),
+		"GOTOOLCHAIN=local",
+	)
+}
@@ -0,0 +1,76 @@
+package internal
+
+import (
+	"fmt"
+	"time"
+)
+
+// Finds the newest patch release in the available versions sharing the
+// minor release of version (e.g. go1.25.6 for go1.25.5). Returns nil
+// when version is already the newest one.
+func (c *Config) LatestPatchRelease(version string) (*RemoteVersion, error) {
+	current, ok := parseReleaseVersion(version)
+	if !ok {
+		return nil, fmt.Errorf("Upgrade Error: %s is not a release version", version)
+	}
+
+	var latest *RemoteVersion
+	latestVersion := current
+
+	for _, remoteVersion := range c.AvailableVersions {
+		candidate, ok := parseReleaseVersion(remoteVersion.Version)
+		if !ok || !candidate.SameMinor(current) {
+			continue
+		}
+
+		if latestVersion.Less(candidate) {
+			latestVersion = candidate
+			latest = &remoteVersion
+		}
+	}
+
+	return latest, nil
+}
+
+// Checks that a downloaded archive really contains the expected version.
+func VerifyArchiveVersion(archivePath string, expected string) error {
+	version, err := ReadArchiveVersion(archivePath)
+	if err != nil {
+		return err
+	}
+
+	// versions scraped from the download page aren't canonical
+	if !SameGoVersion(version, expected) {
+		return fmt.Errorf("Download Error: %s contains %s, expected %s", archivePath, version, expected)
+	}
+
+	return nil
+}
+
+// Builds the notice about a newer patch release of the current version
+// from the cached remote index, without any network access. Returns an
+// empty string when there is nothing to report.
+func (c *Config) UpdateNotice(currentVersion string) string {
+	if c.DisableUpdateNotice {
+		return ""
+	}
+
+	latest, err := c.LatestPatchRelease(currentVersion)
+	if err != nil || latest == nil {
+		return ""
+	}
+
+	latestVersion, _ := ParseGoVersion(latest.Version)
+	current, _ := ParseGoVersion(currentVersion)
+	notice := fmt.Sprintf("Go %s is available (you're on %s). Run 'gvm upgrade' to switch.",
+		latestVersion.Number(),
+		current.Number(),
+	)
+
+	// remind to refresh an index older than a week
+	if c.LastRemoteFetch > 0 && time.Since(time.UnixMilli(c.LastRemoteFetch)) > 7*24*time.Hour {
+		notice += " Run 'gvm list update' to refresh the version list."
+	}
+
+	return notice
+}
@@ -0,0 +1,129 @@
+package internal
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testAvailableVersions(names ...string) []RemoteVersion {
+	versions := make([]RemoteVersion, len(names))
+	for i, name := range names {
+		versions[i] = RemoteVersion{Version: name, DownloadLink: "https://go.dev/dl/" + name + ".linux-amd64.tar.gz"}
+	}
+	return versions
+}
+
+func TestLatestPatchRelease(t *testing.T) {
+	config := &Config{AvailableVersions: testAvailableVersions("go1.26rc1", "go1.25.6", "go1.25.7rc1", "go1.25.5", "go1.24.9", "go1.26.0")}
+
+	tests := []struct {
+		version string
+		want    string
+		wantErr bool
+	}{
+		{version: "go1.25.3", want: "go1.25.6"},
+		{version: "1.25.5", want: "go1.25.6"},
+		{version: "go1.24.2", want: "go1.24.9"},
+		{version: "go1.25.6", want: ""},
+		{version: "go1.24.9", want: ""},
+		{version: "go1.23.4", want: ""},
+		{version: "go1.25", want: "go1.25.6"},
+		{version: "go1.26rc1", wantErr: true},
+		{version: "system-1.22", wantErr: true},
+	}
+
+	for _, test := range tests {
+		latest, err := config.LatestPatchRelease(test.version)
+		if test.wantErr {
+			if err == nil {
+				t.Errorf("LatestPatchRelease(%s) = %v, want error", test.version, latest)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("LatestPatchRelease(%s) = %v", test.version, err)
+			continue
+		}
+
+		got := ""
+		if latest != nil {
+			got = latest.Version
+		}
+		if got != test.want {
+			t.Errorf("LatestPatchRelease(%s) = %q, want %q", test.version, got, test.want)
+		}
+	}
+}
+
+func TestUpdateNotice(t *testing.T) {
+	fresh := time.Now().Add(-time.Hour).UnixMilli()
+	stale := time.Now().Add(-8 * 24 * time.Hour).UnixMilli()
+
+	tests := []struct {
+		name      string
+		config    Config
+		current   string
+		want      string
+		wantStale bool
+	}{
+		{name: "newer patch", config: Config{LastRemoteFetch: fresh}, current: "go1.25.3", want: "Go 1.25.6 is available (you're on 1.25.3)."},
+		{name: "stale index", config: Config{LastRemoteFetch: stale}, current: "go1.25.3", want: "Go 1.25.6 is available", wantStale: true},
+		{name: "never fetched", current: "go1.25.3", want: "Go 1.25.6 is available"},
+		{name: "disabled", config: Config{DisableUpdateNotice: true, LastRemoteFetch: stale}, current: "go1.25.3"},
+		{name: "up to date", config: Config{LastRemoteFetch: stale}, current: "go1.25.6"},
+		{name: "newer than the index", config: Config{LastRemoteFetch: fresh}, current: "go1.25.8"},
+		{name: "not a release", config: Config{LastRemoteFetch: fresh}, current: "devel go1.26-abc"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			test.config.AvailableVersions = testAvailableVersions("go1.26rc1", "go1.25.6", "go1.25.5", "go1.24.9")
+
+			notice := test.config.UpdateNotice(test.current)
+			if test.want == "" {
+				if notice != "" {
+					t.Fatalf("UpdateNotice(%s) = %q, want none", test.current, notice)
+				}
+				return
+			}
+			if !strings.HasPrefix(notice, test.want) {
+				t.Errorf("UpdateNotice(%s) = %q, want it to start with %q", test.current, notice, test.want)
+			}
+			if stale := strings.Contains(notice, "gvm list update"); stale != test.wantStale {
+				t.Errorf("UpdateNotice(%s) = %q, stale index hint %v, want %v", test.current, notice, stale, test.wantStale)
+			}
+		})
+	}
+}
+
+func TestVerifyArchiveVersion(t *testing.T) {
+	archivePath := filepath.Join(t.TempDir(), "go.tar.gz")
+	writeTestTarGz(t, archivePath, []archiveEntry{{name: archiveVersionFile, body: "go1.25.5\n", mode: 0644}})
+
+	tests := []struct {
+		expected string
+		wantErr  bool
+	}{
+		{expected: "go1.25.5"},
+		{expected: "1.25.5"},
+		{expected: " go1.25.5\n"},
+		{expected: "go1.25.4", wantErr: true},
+		{expected: "go1.25", wantErr: true},
+		{expected: "", wantErr: true},
+	}
+
+	for _, test := range tests {
+		err := VerifyArchiveVersion(archivePath, test.expected)
+		if test.wantErr {
+			if err == nil || !strings.HasPrefix(err.Error(), "Download Error:") {
+				t.Errorf("VerifyArchiveVersion(%q) = %v, want Download Error", test.expected, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("VerifyArchiveVersion(%q) = %v", test.expected, err)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package internal
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// Journal of the versions run through the shims, next to the config
+	UsageJournalFile = "usage.log"
+
+	// A shim records a version at most once per interval, so a build
+	// running go hundreds of times counts as a single use.
+	usageJournalThrottle = 10 * time.Minute
+)
+
+// A use of a downloaded version recorded by a shim.
+type usageEntry struct {
+	Version string
+	// Unix milliseconds, like DownloadVersion.LastUsedAt
+	At int64
+}
+
+func UsageJournalPath() (string, error) {
+	configDir, err := ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, UsageJournalFile), nil
+}
+
+// Records a use of the version in the usage journal, which is merged into
+// the config on its next save. Unlike RecordVersionUsage it never takes
+// the config lock, so it's cheap enough for every shim run.
+func JournalVersionUsage(version string, now time.Time) error {
+	journalPath, err := UsageJournalPath()
+	if err != nil {
+		return err
+	}
+
+	entries, err := readUsageJournal(journalPath)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if entry.Version == version && now.Sub(time.UnixMilli(entry.At)) < usageJournalThrottle {
+			return nil
+		}
+	}
+
+	journal, err := os.OpenFile(journalPath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to record usage: %w", err)
+	}
+	defer journal.Close()
+
+	// a single small append, so concurrent shims never interleave lines
+	if _, err := fmt.Fprintf(journal, "%d %s\n", now.UnixMilli(), version); err != nil {
+		return fmt.Errorf("failed to record usage: %w", err)
+	}
+	return nil
+}
+
+// Reads the entries of a usage journal. A missing journal has none and
+// malformed lines, e.g. from a shim killed mid write, are skipped.
+func readUsageJournal(journalPath string) ([]usageEntry, error) {
+	journal, err := os.Open(journalPath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read usage journal: %w", err)
+	}
+	defer journal.Close()
+
+	entries := make([]usageEntry, 0)
+	scanner := bufio.NewScanner(journal)
+	for scanner.Scan() {
+		at, version, ok := strings.Cut(scanner.Text(), " ")
+		millis, err := strconv.ParseInt(at, 10, 64)
+		if !ok || err != nil || version == "" {
+			continue
+		}
+		entries = append(entries, usageEntry{Version: version, At: millis})
+	}
+
+	return entries, scanner.Err()
+}
+
+// Applies journal entries to the usage stats. Counting uses is only done
+// when the journal is consumed, while the last used time can be applied
+// any number of times, e.g. to a config loaded for pruning.
+func (c *Config) applyUsageEntries(entries []usageEntry, countUses bool) {
+	for _, entry := range entries {
+		downloadVersion := c.ResolveDownloadedVersion(entry.Version)
+		if downloadVersion == nil {
+			continue
+		}
+
+		if entry.At > downloadVersion.LastUsedAt {
+			downloadVersion.LastUsedAt = entry.At
+		}
+		if countUses {
+			downloadVersion.UseCount++
+		}
+		c.DownloadedVersions[downloadVersion.Version] = *downloadVersion
+	}
+}
+
+// Moves the journal aside and applies it, with the config lock held.
+// Shims appending meanwhile start a new journal for the next save.
+// Returns a function removing the consumed journal once the config is
+// written, until then a failed save leaves it to be applied again.
+func (c *Config) consumeUsageJournal(configPath string) func() {
+	journalPath := filepath.Join(filepath.Dir(configPath), UsageJournalFile)
+	consumedPath := journalPath + ".merging"
+
+	// a journal left by a failed save comes first
+	if _, err := os.Stat(consumedPath); os.IsNotExist(err) {
+		if err := os.Rename(journalPath, consumedPath); err != nil {
+			return func() {}
+		}
+	}
+
+	entries, err := readUsageJournal(consumedPath)
+	if err != nil {
+		return func() {}
+	}
+	c.applyUsageEntries(entries, true)
+
+	return func() { os.Remove(consumedPath) }
+}
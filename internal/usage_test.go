@@ -0,0 +1,89 @@
+package internal
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestUsageJournal(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	configPath, err := ConfigFilePath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Dir(configPath), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	initial := Config{DownloadedVersions: map[string]DownloadVersion{
+		"go1.25.5": {Version: "go1.25.5", LastUsedAt: 1000, UseCount: 2},
+		"go1.24.2": {Version: "go1.24.2"},
+	}}
+	data, err := json.Marshal(initial)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(configPath, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.UnixMilli(1_000_000_000)
+	uses := []struct {
+		version string
+		at      time.Time
+	}{
+		{"go1.25.5", start},
+		// throttled
+		{"go1.25.5", start.Add(time.Minute)},
+		{"go1.24.2", start.Add(2 * time.Minute)},
+		{"go1.25.5", start.Add(usageJournalThrottle + time.Minute)},
+		// no longer downloaded
+		{"go1.23.1", start},
+	}
+	for _, use := range uses {
+		if err := JournalVersionUsage(use.version, use.at); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	config, err := LoadConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	loaded := config.DownloadedVersions["go1.25.5"]
+	if loaded.LastUsedAt != start.Add(usageJournalThrottle+time.Minute).UnixMilli() || loaded.UseCount != 2 {
+		t.Errorf("loaded usage = %d uses, last %d; want journal time and uncounted uses", loaded.UseCount, loaded.LastUsedAt)
+	}
+
+	if err := config.Save(); err != nil {
+		t.Fatal(err)
+	}
+	journalPath, _ := UsageJournalPath()
+	if _, err := os.Stat(journalPath); !os.IsNotExist(err) {
+		t.Errorf("journal not consumed by save: %v", err)
+	}
+
+	// loading and saving again must not count the uses twice
+	config, err = LoadConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := config.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]DownloadVersion{
+		"go1.25.5": {LastUsedAt: start.Add(usageJournalThrottle + time.Minute).UnixMilli(), UseCount: 4},
+		"go1.24.2": {LastUsedAt: start.Add(2 * time.Minute).UnixMilli(), UseCount: 1},
+	}
+	for name, want := range want {
+		got := config.DownloadedVersions[name]
+		if got.LastUsedAt != want.LastUsedAt || got.UseCount != want.UseCount {
+			t.Errorf("%s: %d uses, last %d; want %d uses, last %d", name, got.UseCount, got.LastUsedAt, want.UseCount, want.LastUsedAt)
+		}
+	}
+}
@@ -0,0 +1,209 @@
+package internal
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// A golang release version, e.g. go1, go1.9, go1.21rc2 or go1.25.5.
+// Versions written without a minor or patch number treat it as 0 for
+// ordering but keep their original form when formatted.
+type GoVersion struct {
+	Major int
+	Minor int
+	Patch int
+	// "beta" or "rc" for prereleases, empty for releases
+	Prerelease    string
+	PrereleaseNum int
+	// number of dot separated components written, e.g. 2 for go1.21
+	components int
+}
+
+// Matches every historical release name. The "go" prefix is optional and
+// a "v" prefix is accepted for compatibility with semver style input.
+// Prereleases are named without a patch number, e.g. go1.21rc1, never
+// go1.21.0rc1, and there is no major version 0.
+var goVersionRegex = regexp.MustCompile(`^(?:go|v)?([1-9]\d*)(?:\.(0|[1-9]\d*)(?:\.(0|[1-9]\d*)|(beta|rc)([1-9]\d*))?)?$`)
+
+// Parses a golang release name such as "go1.25.5", "1.21rc2", "1.9beta1"
+// or "go1". Development builds and linked names are not versions.
+func ParseGoVersion(version string) (GoVersion, error) {
+	match := goVersionRegex.FindStringSubmatch(strings.TrimSpace(version))
+	if match == nil {
+		return GoVersion{}, fmt.Errorf("Input Error: '%s' is not a valid golang version", version)
+	}
+
+	v := GoVersion{components: 1, Prerelease: match[4]}
+	v.Major, _ = strconv.Atoi(match[1])
+	if match[2] != "" {
+		v.Minor, _ = strconv.Atoi(match[2])
+		v.components = 2
+	}
+	if match[3] != "" {
+		v.Patch, _ = strconv.Atoi(match[3])
+		v.components = 3
+	}
+	if match[5] != "" {
+		v.PrereleaseNum, _ = strconv.Atoi(match[5])
+	}
+
+	return v, nil
+}
+
+// Parses a semver string of the form X.Y.Z[-prerelease.N] as used by the
+// go vulnerability database ("1.21.0-rc.2") and gvm's own releases.
+// A bare "-0" prerelease sorts before every prerelease of the release.
+func parseSemver(version string) (GoVersion, bool) {
+	core, pre, hasPre := strings.Cut(strings.TrimPrefix(version, "v"), "-")
+
+	parts := strings.Split(core, ".")
+	if len(parts) > 3 {
+		return GoVersion{}, false
+	}
+
+	numbers := make([]int, 3)
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return GoVersion{}, false
+		}
+		numbers[i] = n
+	}
+
+	v := GoVersion{Major: numbers[0], Minor: numbers[1], Patch: numbers[2], components: 3}
+	if hasPre {
+		name, num, _ := strings.Cut(pre, ".")
+		if name == "0" {
+			// lowest possible prerelease, beta0 sorts before beta1 and rc
+			name = "beta"
+		}
+		v.Prerelease = name
+		v.PrereleaseNum, _ = strconv.Atoi(num)
+		if v.Prerelease == "" {
+			return GoVersion{}, false
+		}
+	}
+
+	// golang names x.y.0 releases before go1.21 and all prereleases
+	// without the patch number, e.g. go1.20 and go1.21rc2
+	if v.Patch == 0 && (v.IsPrerelease() || (v.Major == 1 && v.Minor < 21)) {
+		v.components = 2
+	}
+
+	return v, true
+}
+
+// Reports whether version is a prerelease (beta or release candidate).
+// Names which aren't versions (linked, devel) are no prereleases.
+func IsPrereleaseVersion(version string) bool {
+	v, err := ParseGoVersion(version)
+	return err == nil && v.IsPrerelease()
+}
+
+// Reports whether the version is a beta or release candidate.
+func (v GoVersion) IsPrerelease() bool {
+	return v.Prerelease != ""
+}
+
+// Canonical release name, e.g. "go1.21rc2".
+func (v GoVersion) String() string {
+	return "go" + v.Number()
+}
+
+// Release name without the "go" prefix as accepted by the gvm commands,
+// e.g. "1.21rc2".
+func (v GoVersion) Number() string {
+	number := strconv.Itoa(v.Major)
+	if v.components >= 2 {
+		number += fmt.Sprintf(".%d", v.Minor)
+	}
+	if v.components >= 3 {
+		number += fmt.Sprintf(".%d", v.Patch)
+	}
+	if v.IsPrerelease() {
+		number += fmt.Sprintf("%s%d", v.Prerelease, v.PrereleaseNum)
+	}
+	return number
+}
+
+// Reports whether the version was written with a patch number, e.g.
+// go1.25.5 but not go1.25 or go1.21rc2.
+func (v GoVersion) HasPatch() bool {
+	return v.components >= 3
+}
+
+// Name of the minor release line, e.g. "go1.25" for go1.25.5.
+func (v GoVersion) MinorLine() string {
+	return fmt.Sprintf("go%d.%d", v.Major, v.Minor)
+}
+
+// Reports whether both versions belong to the same minor release line.
+func (v GoVersion) SameMinor(other GoVersion) bool {
+	return v.Major == other.Major && v.Minor == other.Minor
+}
+
+// Semver form used by the go vulnerability database, e.g. "1.21.0-rc.2".
+func (v GoVersion) Semver() string {
+	semver := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if v.IsPrerelease() {
+		semver += fmt.Sprintf("-%s.%d", v.Prerelease, v.PrereleaseNum)
+	}
+	return semver
+}
+
+// Compares two versions, returning -1, 0 or 1. Numbers compare
+// numerically (go1.9 < go1.10) and prereleases sort before their release
+// (go1.21beta1 < go1.21rc2 < go1.21.0). Omitted numbers count as 0, so
+// go1.20 and go1.20.0 are equal.
+func (v GoVersion) Compare(other GoVersion) int {
+	for _, pair := range [][2]int{
+		{v.Major, other.Major},
+		{v.Minor, other.Minor},
+		{v.Patch, other.Patch},
+	} {
+		if pair[0] != pair[1] {
+			if pair[0] < pair[1] {
+				return -1
+			}
+			return 1
+		}
+	}
+
+	switch {
+	case v.Prerelease == other.Prerelease:
+		if v.PrereleaseNum == other.PrereleaseNum {
+			return 0
+		}
+		if v.PrereleaseNum < other.PrereleaseNum {
+			return -1
+		}
+		return 1
+	case v.Prerelease == "":
+		return 1
+	case other.Prerelease == "":
+		return -1
+	case v.Prerelease < other.Prerelease:
+		return -1
+	default:
+		return 1
+	}
+}
+
+// Reports whether v is an older version than other.
+func (v GoVersion) Less(other GoVersion) bool {
+	return v.Compare(other) < 0
+}
+
+// Reports whether both names denote the same version, e.g. "1.25.5" and
+// "go1.25.5". Names which aren't versions only match themselves.
+func SameGoVersion(a string, b string) bool {
+	if strings.TrimSpace(a) == strings.TrimSpace(b) {
+		return true
+	}
+
+	va, errA := ParseGoVersion(a)
+	vb, errB := ParseGoVersion(b)
+	return errA == nil && errB == nil && va.Compare(vb) == 0
+}
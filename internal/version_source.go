@@ -0,0 +1,217 @@
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// Names of the version sources selectable via Config.VersionSource.
+const (
+	VersionSourceGoDev  = "go.dev"
+	VersionSourceFile   = "file"
+	VersionSourceMirror = "mirror"
+	VersionSourceGitHub = "github"
+)
+
+// Official golang release feed in JSON
+const GO_DEV_RELEASES_URL = "https://go.dev/dl/?mode=json&include=all"
+
+// Base url official golang release archives are served from
+const GO_DEV_DOWNLOAD_URL = "https://go.dev/dl"
+
+// A source of remote golang versions available for download.
+// Versions are expected to be returned newest first.
+type VersionSource interface {
+	// Human readable name shown while fetching
+	Name() string
+	FetchVersions() ([]RemoteVersion, error)
+}
+
+// A version source which lists the archives of every platform, needed
+// to lock toolchains for platforms other than the running one.
+type PlatformVersionSource interface {
+	VersionSource
+	// Versions available for each of the "goos/goarch" platforms
+	FetchPlatformVersions(platforms []string) (map[string][]RemoteVersion, error)
+}
+
+// Creates the version source of the given kind. location is the
+// path of the index file for VersionSourceFile and the base url of
+// the mirror for VersionSourceMirror, it is ignored otherwise.
+func NewVersionSource(kind string, location string) (VersionSource, error) {
+	switch kind {
+	case "", VersionSourceGitHub:
+		return &GitHubVersionSource{}, nil
+	case VersionSourceGoDev:
+		return &GoDevVersionSource{URL: GO_DEV_RELEASES_URL}, nil
+	case VersionSourceFile:
+		if location == "" {
+			return nil, fmt.Errorf("Config Error: version source '%s' requires a file path", kind)
+		}
+		return &FileVersionSource{Path: location}, nil
+	case VersionSourceMirror:
+		if location == "" {
+			return nil, fmt.Errorf("Config Error: version source '%s' requires a mirror url", kind)
+		}
+		return &MirrorVersionSource{BaseURL: location}, nil
+	default:
+		return nil, fmt.Errorf("Config Error: unknown version source '%s'. Expected one of %s, %s, %s, %s", kind, VersionSourceGoDev, VersionSourceFile, VersionSourceMirror, VersionSourceGitHub)
+	}
+}
+
+// Legacy source scraping the tags page of the golang github repository.
+type GitHubVersionSource struct{}
+
+func (s *GitHubVersionSource) Name() string {
+	return "GitHub"
+}
+
+func (s *GitHubVersionSource) FetchVersions() ([]RemoteVersion, error) {
+	return FetchGoVersionsFromGoGithubRelease()
+}
+
+// Source reading the official JSON release feed of go.dev.
+type GoDevVersionSource struct {
+	URL string
+}
+
+func (s *GoDevVersionSource) Name() string {
+	return "go.dev"
+}
+
+func (s *GoDevVersionSource) FetchVersions() ([]RemoteVersion, error) {
+	return currentPlatformVersions(s)
+}
+
+func (s *GoDevVersionSource) FetchPlatformVersions(platforms []string) (map[string][]RemoteVersion, error) {
+	body, err := fetchIndex(s.URL)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	return parseReleaseIndex(body, GO_DEV_DOWNLOAD_URL, platforms)
+}
+
+// Source reading a release index in the go.dev JSON format from a
+// local file, e.g. one distributed to air-gapped machines.
+type FileVersionSource struct {
+	Path string
+}
+
+func (s *FileVersionSource) Name() string {
+	return s.Path
+}
+
+func (s *FileVersionSource) FetchVersions() ([]RemoteVersion, error) {
+	return currentPlatformVersions(s)
+}
+
+func (s *FileVersionSource) FetchPlatformVersions(platforms []string) (map[string][]RemoteVersion, error) {
+	file, err := os.Open(s.Path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open version index: %w", err)
+	}
+	defer file.Close()
+
+	return parseReleaseIndex(file, GO_DEV_DOWNLOAD_URL, platforms)
+}
+
+// Source for mirrors of go.dev/dl. The mirror serves the release
+// archives under its base url and the JSON index at <base>/?mode=json.
+type MirrorVersionSource struct {
+	BaseURL string
+}
+
+func (s *MirrorVersionSource) Name() string {
+	return s.BaseURL
+}
+
+func (s *MirrorVersionSource) FetchVersions() ([]RemoteVersion, error) {
+	return currentPlatformVersions(s)
+}
+
+func (s *MirrorVersionSource) FetchPlatformVersions(platforms []string) (map[string][]RemoteVersion, error) {
+	baseURL := strings.TrimSuffix(s.BaseURL, "/")
+
+	body, err := fetchIndex(baseURL + "/?mode=json&include=all")
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	return parseReleaseIndex(body, baseURL, platforms)
+}
+
+func currentPlatformVersions(source PlatformVersionSource) ([]RemoteVersion, error) {
+	platform := CurrentPlatform()
+	versions, err := source.FetchPlatformVersions([]string{platform})
+	if err != nil {
+		return nil, err
+	}
+	return versions[platform], nil
+}
+
+func fetchIndex(url string) (io.ReadCloser, error) {
+	response, err := HTTPClient().Get(url)
+	if err != nil {
+		return nil, err
+	}
+
+	if response.StatusCode != http.StatusOK {
+		response.Body.Close()
+		return nil, fmt.Errorf("Failed to fetch go versions from %s. Status Code: %d. Status: %s", url, response.StatusCode, response.Status)
+	}
+
+	return response.Body, nil
+}
+
+// Single release of the go.dev JSON feed
+type releaseIndexEntry struct {
+	Version string `json:"version"`
+	Stable  bool   `json:"stable"`
+	Files   []struct {
+		Filename string `json:"filename"`
+		OS       string `json:"os"`
+		Arch     string `json:"arch"`
+		SHA256   string `json:"sha256"`
+		Kind     string `json:"kind"`
+	} `json:"files"`
+}
+
+// Parses a release index in the go.dev JSON format and keeps the
+// archive of every release built for each of the platforms.
+func parseReleaseIndex(r io.Reader, downloadBaseURL string, platforms []string) (map[string][]RemoteVersion, error) {
+	var entries []releaseIndexEntry
+	if err := json.NewDecoder(r).Decode(&entries); err != nil {
+		return nil, fmt.Errorf("failed to parse version index: %w", err)
+	}
+
+	releases := make(map[string][]RemoteVersion, len(platforms))
+	for _, platform := range platforms {
+		releases[platform] = make([]RemoteVersion, 0, len(entries))
+	}
+
+	for _, entry := range entries {
+		added := make(map[string]bool)
+		for _, file := range entry.Files {
+			platform := file.OS + "/" + file.Arch
+			if _, wanted := releases[platform]; !wanted || added[platform] || file.Kind != "archive" {
+				continue
+			}
+
+			releases[platform] = append(releases[platform], RemoteVersion{
+				Version:      entry.Version,
+				DownloadLink: fmt.Sprintf("%s/%s", downloadBaseURL, file.Filename),
+				SHA256:       file.SHA256,
+			})
+			added[platform] = true
+		}
+	}
+
+	return releases, nil
+}
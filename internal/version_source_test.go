@@ -0,0 +1,124 @@
+package internal
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseReleaseIndex(t *testing.T) {
+	file, err := os.Open(filepath.Join("testdata", "releases.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	platforms := []string{"linux/amd64", "windows/amd64", "plan9/386"}
+	releases, err := parseReleaseIndex(file, "https://mirror.example/dl", platforms)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantVersions := map[string][]string{
+		"linux/amd64":   {"go1.26rc1", "go1.25.5", "go1.25.3", "go1.25.0", "go1.24.2"},
+		"windows/amd64": {"go1.26rc1", "go1.25.5", "go1.25.0", "go1.24.2"},
+		"plan9/386":     {},
+	}
+	if len(releases) != len(wantVersions) {
+		t.Errorf("parseReleaseIndex() returned platforms %v", releases)
+	}
+	for platform, want := range wantVersions {
+		versions := make([]string, 0)
+		for _, release := range releases[platform] {
+			versions = append(versions, release.Version)
+		}
+		if !reflect.DeepEqual(versions, want) {
+			t.Errorf("%s versions = %v, want %v", platform, versions, want)
+		}
+	}
+
+	// only the archive is kept, never the installer or the source
+	windows := releases["windows/amd64"][1]
+	if windows.DownloadLink != "https://mirror.example/dl/go1.25.5.windows-amd64.zip" {
+		t.Errorf("windows download link = %s", windows.DownloadLink)
+	}
+	linux := releases["linux/amd64"][1]
+	if linux.DownloadLink != "https://mirror.example/dl/go1.25.5.linux-amd64.tar.gz" || linux.SHA256 != "471ceb5c72bb60c4b5d1d20bac6bf18526f3b7994fcb4fa0512e8cf49d11ab2d" {
+		t.Errorf("linux release = %+v", linux)
+	}
+}
+
+func TestParseReleaseIndexInvalid(t *testing.T) {
+	if _, err := parseReleaseIndex(strings.NewReader(`{"version": "go1.25.5"}`), GO_DEV_DOWNLOAD_URL, []string{"linux/amd64"}); err == nil {
+		t.Error("parseReleaseIndex() accepted an index which isn't an array")
+	}
+}
+
+func TestConfigGetVersionSource(t *testing.T) {
+	tests := []struct {
+		name     string
+		kind     string
+		location string
+		want     VersionSource
+		wantErr  bool
+	}{
+		{name: "default", want: &GitHubVersionSource{}},
+		{name: "github", kind: VersionSourceGitHub, want: &GitHubVersionSource{}},
+		{name: "go.dev ignores location", kind: VersionSourceGoDev, location: "https://elsewhere.example", want: &GoDevVersionSource{URL: GO_DEV_RELEASES_URL}},
+		{name: "file", kind: VersionSourceFile, location: "/srv/releases.json", want: &FileVersionSource{Path: "/srv/releases.json"}},
+		{name: "file without path", kind: VersionSourceFile, wantErr: true},
+		{name: "mirror", kind: VersionSourceMirror, location: "https://mirror.example/golang", want: &MirrorVersionSource{BaseURL: "https://mirror.example/golang"}},
+		{name: "mirror without url", kind: VersionSourceMirror, wantErr: true},
+		{name: "unknown", kind: "ftp", location: "ftp://mirror.example", wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			config := &Config{VersionSource: test.kind, VersionSourceURL: test.location}
+			source, err := config.GetVersionSource()
+			if test.wantErr {
+				if err == nil {
+					t.Fatalf("GetVersionSource() = %#v, want error", source)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetVersionSource() = %v", err)
+			}
+			if !reflect.DeepEqual(source, test.want) {
+				t.Errorf("GetVersionSource() = %#v, want %#v", source, test.want)
+			}
+		})
+	}
+}
+
+func TestMirrorVersionSource(t *testing.T) {
+	index, err := os.ReadFile(filepath.Join("testdata", "releases.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/golang/" || r.URL.Query().Get("mode") != "json" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write(index)
+	}))
+	defer server.Close()
+
+	source := &MirrorVersionSource{BaseURL: server.URL + "/golang/"}
+	releases, err := source.FetchPlatformVersions([]string{"darwin/arm64"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	darwin := releases["darwin/arm64"]
+	if len(darwin) != 5 || darwin[0].DownloadLink != server.URL+"/golang/go1.26rc1.darwin-arm64.tar.gz" {
+		t.Errorf("mirror releases = %+v", darwin)
+	}
+}
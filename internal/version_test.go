@@ -0,0 +1,146 @@
+package internal
+
+import (
+	"sort"
+	"testing"
+)
+
+func TestParseGoVersion(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    string
+		wantNum string
+		patch   bool
+		prerel  bool
+		wantErr bool
+	}{
+		{input: "go1.25.5", want: "go1.25.5", wantNum: "1.25.5", patch: true},
+		{input: "1.25.5", want: "go1.25.5", wantNum: "1.25.5", patch: true},
+		{input: "v1.25.5", want: "go1.25.5", wantNum: "1.25.5", patch: true},
+		{input: " go1.25 ", want: "go1.25", wantNum: "1.25"},
+		{input: "go1.9", want: "go1.9", wantNum: "1.9"},
+		{input: "go1.10", want: "go1.10", wantNum: "1.10"},
+		{input: "go1", want: "go1", wantNum: "1"},
+		{input: "go1.21beta1", want: "go1.21beta1", wantNum: "1.21beta1", prerel: true},
+		{input: "go1.21rc2", want: "go1.21rc2", wantNum: "1.21rc2", prerel: true},
+		{input: "1.21rc2", want: "go1.21rc2", wantNum: "1.21rc2", prerel: true},
+		{input: "go1.", wantErr: true},
+		{input: "go", wantErr: true},
+		{input: "", wantErr: true},
+		{input: "go1.25.05", wantErr: true},
+		{input: "go1.21rc", wantErr: true},
+		{input: "go1.21rc0", wantErr: true},
+		{input: "go1.21alpha1", wantErr: true},
+		{input: "go1.25.5.1", wantErr: true},
+		{input: "go1.21.0rc1", wantErr: true},
+		{input: "1.21.1beta1", wantErr: true},
+		{input: "go1rc1", wantErr: true},
+		{input: "0", wantErr: true},
+		{input: "go0.9", wantErr: true},
+		{input: "v0.1.0", wantErr: true},
+		{input: "devel go1.26-abc", wantErr: true},
+		{input: "system-1.22", wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.input, func(t *testing.T) {
+			v, err := ParseGoVersion(test.input)
+			if test.wantErr {
+				if err == nil {
+					t.Fatalf("ParseGoVersion(%q) = %s, want error", test.input, v)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseGoVersion(%q) = %v", test.input, err)
+			}
+			if v.String() != test.want || v.Number() != test.wantNum {
+				t.Errorf("ParseGoVersion(%q) = %s (%s), want %s (%s)", test.input, v, v.Number(), test.want, test.wantNum)
+			}
+			if v.HasPatch() != test.patch || v.IsPrerelease() != test.prerel {
+				t.Errorf("ParseGoVersion(%q): HasPatch() = %v, IsPrerelease() = %v", test.input, v.HasPatch(), v.IsPrerelease())
+			}
+		})
+	}
+}
+
+func TestGoVersionCompare(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{a: "go1.9", b: "go1.10", want: -1},
+		{a: "go1.9.5", b: "go1.10", want: -1},
+		{a: "go1.25.10", b: "go1.25.9", want: 1},
+		{a: "go1.21beta1", b: "go1.21rc1", want: -1},
+		{a: "go1.21rc1", b: "go1.21rc2", want: -1},
+		{a: "go1.21rc2", b: "go1.21.0", want: -1},
+		{a: "go1.21rc2", b: "go1.20.14", want: 1},
+		{a: "go1.20", b: "go1.20.0", want: 0},
+		{a: "go1", b: "go1.0.0", want: 0},
+		{a: "1.25.5", b: "go1.25.5", want: 0},
+		{a: "go2", b: "go1.99.99", want: 1},
+	}
+
+	for _, test := range tests {
+		a, errA := ParseGoVersion(test.a)
+		b, errB := ParseGoVersion(test.b)
+		if errA != nil || errB != nil {
+			t.Fatalf("failed to parse %s or %s", test.a, test.b)
+		}
+
+		if got := a.Compare(b); got != test.want {
+			t.Errorf("%s.Compare(%s) = %d, want %d", test.a, test.b, got, test.want)
+		}
+		if got := b.Compare(a); got != -test.want {
+			t.Errorf("%s.Compare(%s) = %d, want %d", test.b, test.a, got, -test.want)
+		}
+		if a.Less(b) != (test.want < 0) {
+			t.Errorf("%s.Less(%s) = %v", test.a, test.b, a.Less(b))
+		}
+	}
+}
+
+func TestGoVersionSort(t *testing.T) {
+	names := []string{"go1.21.0", "go1.10", "go1.21rc2", "go1.9.2", "go1.21beta1", "go1.20.14", "go1.9", "go1.21.13"}
+	want := []string{"go1.9", "go1.9.2", "go1.10", "go1.20.14", "go1.21beta1", "go1.21rc2", "go1.21.0", "go1.21.13"}
+
+	versions := make([]GoVersion, len(names))
+	for i, name := range names {
+		v, err := ParseGoVersion(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		versions[i] = v
+	}
+	sort.Slice(versions, func(i, j int) bool { return versions[i].Less(versions[j]) })
+
+	for i, v := range versions {
+		if v.String() != want[i] {
+			t.Fatalf("sorted versions = %v, want %v", versions, want)
+		}
+	}
+}
+
+func TestParseSemver(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+		ok    bool
+	}{
+		{input: "1.21.0", want: "go1.21.0", ok: true},
+		{input: "v1.21.3", want: "go1.21.3", ok: true},
+		{input: "1.21.0-rc.2", want: "go1.21rc2", ok: true},
+		{input: "1.20.0", want: "go1.20", ok: true},
+		{input: "1.21.0-", ok: false},
+		{input: "1.x.0", ok: false},
+		{input: "1.2.3.4", ok: false},
+	}
+
+	for _, test := range tests {
+		v, ok := parseSemver(test.input)
+		if ok != test.ok || (ok && v.String() != test.want) {
+			t.Errorf("parseSemver(%q) = %s, %v, want %s, %v", test.input, v, ok, test.want, test.ok)
+		}
+	}
+}